@@ -1,7 +1,6 @@
 package tsid
 
 import (
-	"fmt"
 	"sync"
 	"testing"
 )
@@ -38,7 +37,7 @@ func BenchmarkGenerate(b *testing.B) {
 					Build()
 
 				if err != nil {
-					fmt.Errorf("Failed to instantiate tsid factory with error: %s", err)
+					b.Errorf("Failed to instantiate tsid factory with error: %s", err)
 					return
 				}
 
@@ -53,3 +52,41 @@ func BenchmarkGenerate(b *testing.B) {
 	})
 
 }
+
+// BenchmarkGenerateParallel compares the mutex and the lock-free generators
+// on a shared factory. Run with -cpu=1,4,16 to compare contention
+func BenchmarkGenerateParallel(b *testing.B) {
+
+	b.Run("mutex", func(b *testing.B) {
+		tsidFactory, err := TsidFactoryBuilder().
+			WithClock(NewSystemClock()).
+			NewInstance()
+
+		if err != nil {
+			b.FailNow()
+		}
+
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				tsidFactory.Generate()
+			}
+		})
+	})
+
+	b.Run("lock-free", func(b *testing.B) {
+		tsidFactory, err := TsidFactoryBuilder().
+			WithClock(NewSystemClock()).
+			WithLockFree(true).
+			NewInstance()
+
+		if err != nil {
+			b.FailNow()
+		}
+
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				tsidFactory.Generate()
+			}
+		})
+	})
+}
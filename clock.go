@@ -16,6 +16,37 @@ limitations under the License.
 
 package tsid
 
+import "time"
+
 type Clock interface {
 	UnixMilli() int64
 }
+
+// systemClock reads the wall clock on every call. It is the default clock
+type systemClock struct {
+}
+
+func NewSystemClock() *systemClock {
+	return &systemClock{}
+}
+
+func (c *systemClock) UnixMilli() int64 {
+	return time.Now().UnixMilli()
+}
+
+// monotonicClock reads the wall clock once, and then advances it using
+// the monotonic clock. Tsids remain ordered even if the wall clock is
+// adjusted, at the cost of drifting from it
+type monotonicClock struct {
+	anchor time.Time
+}
+
+func NewMonotonicClock() *monotonicClock {
+	return &monotonicClock{
+		anchor: time.Now(),
+	}
+}
+
+func (c *monotonicClock) UnixMilli() int64 {
+	return c.anchor.Add(time.Since(c.anchor)).UnixMilli()
+}
@@ -0,0 +1,76 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SystemClock(t *testing.T) {
+
+	t.Run("should return current time", func(t *testing.T) {
+		clock := NewSystemClock()
+
+		for i := 0; i < 3; i++ {
+			start := time.Now().UnixMilli()
+			millis := clock.UnixMilli()
+			end := time.Now().UnixMilli()
+
+			assert.GreaterOrEqual(t, millis, start)
+			assert.LessOrEqual(t, millis, end)
+
+			time.Sleep(2 * time.Millisecond)
+		}
+	})
+
+	t.Run("default factory should use current time", func(t *testing.T) {
+		tsidFactory, err := TsidFactoryBuilder().
+			NewInstance()
+		assert.Nil(t, err)
+
+		time.Sleep(5 * time.Millisecond)
+
+		start := time.Now().UnixMilli()
+		tsid, err := tsidFactory.Generate()
+		assert.Nil(t, err)
+
+		assert.GreaterOrEqual(t, tsid.GetUnixMillis(), start)
+	})
+}
+
+func Test_MonotonicClock(t *testing.T) {
+
+	t.Run("should advance from the wall clock", func(t *testing.T) {
+		start := time.Now().UnixMilli()
+		clock := NewMonotonicClock()
+
+		last := clock.UnixMilli()
+		assert.GreaterOrEqual(t, last, start)
+
+		for i := 0; i < 3; i++ {
+			time.Sleep(2 * time.Millisecond)
+
+			millis := clock.UnixMilli()
+			assert.GreaterOrEqual(t, millis, last+2)
+			assert.LessOrEqual(t, millis, time.Now().UnixMilli()+1)
+			last = millis
+		}
+	})
+}
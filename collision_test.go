@@ -55,11 +55,8 @@ func TestCollision(t *testing.T) {
 					tsid, err := tsidFactory.Generate()
 					assert.Nil(t, err)
 
-					// check if this tsid was already generated
-					if _, ok := tsidMap.Load(tsid); !ok {
-
-						// not present, store it
-						tsidMap.Store(tsid, (nodeId*iterationCount)+int32(j))
+					// store the tsid unless it was already generated
+					if _, loaded := tsidMap.LoadOrStore(tsid, (nodeId*iterationCount)+int32(j)); !loaded {
 						continue
 					}
 
@@ -75,7 +72,7 @@ func TestCollision(t *testing.T) {
 		// Wait for all goroutines to complete
 		wg.Wait()
 
-		assert.Zero(t, collisionCounter.Load(), 0, "Collision detected")
+		assert.Zero(t, collisionCounter.Load(), "Collision detected")
 	})
 
 	t.Run("multiple goroutines per node", func(t *testing.T) {
@@ -106,11 +103,8 @@ func TestCollision(t *testing.T) {
 					tsid, err := tsidFactory.Generate()
 					assert.Nil(t, err)
 
-					// check if this tsid was already generated
-					if _, ok := tsidMap.Load(tsid); !ok {
-
-						// not present, store it
-						tsidMap.Store(tsid, (nodeId*iterationCount)+int32(j))
+					// store the tsid unless it was already generated
+					if _, loaded := tsidMap.LoadOrStore(tsid, (nodeId*iterationCount)+int32(j)); !loaded {
 						continue
 					}
 
@@ -126,6 +120,6 @@ func TestCollision(t *testing.T) {
 		// Wait for all goroutines to complete
 		wg.Wait()
 
-		assert.Zero(t, collisionCounter.Load(), 0, "Collision detected")
+		assert.Zero(t, collisionCounter.Load(), "Collision detected")
 	})
 }
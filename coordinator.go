@@ -0,0 +1,302 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// NodeCoordinator leases node ids across a cluster. A lease expires unless
+// it is renewed in time, so that the node of a crashed instance can be
+// acquired again by another one
+type NodeCoordinator interface {
+	// Acquire leases a free node id within the node bits to the owner.
+	// It returns ErrNodeUnavailable if all the node ids are leased, and
+	// ErrInvalidLease if the node bits or ttl are out of range. The ttl
+	// must be at least a millisecond
+	Acquire(ctx context.Context, nodeBits int32, owner string, ttl time.Duration) (NodeLease, error)
+
+	// Renew extends the lease. It returns ErrLeaseLost if the lease has
+	// expired or is held by another owner
+	Renew(ctx context.Context, lease NodeLease, ttl time.Duration) (NodeLease, error)
+
+	// Release gives up the lease, so that the node id can be acquired
+	// by another owner
+	Release(ctx context.Context, lease NodeLease) error
+}
+
+// NodeLease is a node id leased to an owner until the expiry
+type NodeLease struct {
+	Node   int32
+	Owner  string
+	Expiry time.Time
+}
+
+// memoryNodeCoordinator leases node ids within a single process. It is the
+// reference implementation of NodeCoordinator, and can be served to other
+// processes using NewNodeCoordinatorHandler
+type memoryNodeCoordinator struct {
+	mu     sync.Mutex
+	clock  Clock
+	leases map[int32]NodeLease
+}
+
+func NewMemoryNodeCoordinator() *memoryNodeCoordinator {
+	return &memoryNodeCoordinator{
+		clock:  NewSystemClock(),
+		leases: make(map[int32]NodeLease),
+	}
+}
+
+func (c *memoryNodeCoordinator) Acquire(ctx context.Context, nodeBits int32, owner string, ttl time.Duration) (NodeLease, error) {
+	if err := validateLease(nodeBits, ttl); err != nil {
+		return NodeLease{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.UnixMilli(c.clock.UnixMilli())
+	max := mask(nodeBits)
+	for node := int32(0); node <= max; node++ {
+		lease, ok := c.leases[node]
+		if ok && lease.Owner != owner && now.Before(lease.Expiry) {
+			continue
+		}
+
+		lease = NodeLease{Node: node, Owner: owner, Expiry: now.Add(ttl)}
+		c.leases[node] = lease
+		return lease, nil
+	}
+	return NodeLease{}, fmt.Errorf("%w: all %d node ids are leased", ErrNodeUnavailable, max+1)
+}
+
+func (c *memoryNodeCoordinator) Renew(ctx context.Context, lease NodeLease, ttl time.Duration) (NodeLease, error) {
+	if err := validateLeaseTTL(ttl); err != nil {
+		return NodeLease{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.UnixMilli(c.clock.UnixMilli())
+	current, ok := c.leases[lease.Node]
+	if !ok || current.Owner != lease.Owner || !now.Before(current.Expiry) {
+		return NodeLease{}, fmt.Errorf("%w: node %d", ErrLeaseLost, lease.Node)
+	}
+
+	current.Expiry = now.Add(ttl)
+	c.leases[lease.Node] = current
+	return current, nil
+}
+
+func (c *memoryNodeCoordinator) Release(ctx context.Context, lease NodeLease) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if current, ok := c.leases[lease.Node]; ok && current.Owner == lease.Owner {
+		delete(c.leases, lease.Node)
+	}
+	return nil
+}
+
+// minLeaseTTL is the shortest ttl of a lease. Leases are exchanged in
+// milliseconds, and renewed every third of their ttl
+const minLeaseTTL = time.Millisecond
+
+// validateLease checks that the node bits are within [0, 20], like
+// WithNodeBits, and that the ttl is valid
+func validateLease(nodeBits int32, ttl time.Duration) error {
+	if nodeBits < 0 || nodeBits > 20 {
+		return fmt.Errorf("%w: node bits out of range [0, 20]: %d", ErrInvalidLease, nodeBits)
+	}
+	return validateLeaseTTL(ttl)
+}
+
+// validateLeaseTTL checks that the ttl is at least minLeaseTTL
+func validateLeaseTTL(ttl time.Duration) error {
+	if ttl < minLeaseTTL {
+		return fmt.Errorf("%w: ttl must be at least %s: %s", ErrInvalidLease, minLeaseTTL, ttl)
+	}
+	return nil
+}
+
+// nodeLeaseKeeper keeps the node lease of a factory alive. The lease is
+// renewed every third of its ttl, and is considered lost once renewal
+// fails with ErrLeaseLost or the ttl elapses without a renewal. The ttl
+// is measured with the monotonic clock, independent of the factory clock
+type nodeLeaseKeeper struct {
+	coordinator NodeCoordinator
+	now         func() time.Time
+	start       time.Time
+	ttl         time.Duration
+
+	node   int32
+	mu     sync.Mutex // guards lease
+	lease  NodeLease
+	expiry atomic.Int64 // local expiry in nanos since start, zero once lost
+	stop   chan struct{}
+	done   chan struct{}
+	once   sync.Once
+}
+
+// acquireNodeLease acquires a node lease and starts renewing it
+func acquireNodeLease(coordinator NodeCoordinator, now func() time.Time, nodeBits int32, ttl time.Duration) (*nodeLeaseKeeper, error) {
+	if err := validateLease(nodeBits, ttl); err != nil {
+		return nil, err
+	}
+
+	keeper := &nodeLeaseKeeper{
+		coordinator: coordinator,
+		now:         now,
+		start:       now(),
+		ttl:         ttl,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ttl)
+	defer cancel()
+
+	start := keeper.elapsed()
+	lease, err := coordinator.Acquire(ctx, nodeBits, newLeaseOwner(), ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	max := mask(nodeBits)
+	if lease.Node < 0 || lease.Node > max {
+		coordinator.Release(ctx, lease)
+		return nil, fmt.Errorf("%w [0, %d]: leased node %d", ErrNodeOutOfRange, max, lease.Node)
+	}
+
+	keeper.node = lease.Node
+	keeper.lease = lease
+	keeper.expiry.Store(int64(start + ttl))
+
+	go keeper.heartbeat()
+	return keeper, nil
+}
+
+// heartbeat renews the lease until it is lost or the keeper is closed
+func (k *nodeLeaseKeeper) heartbeat() {
+	defer close(k.done)
+
+	ticker := time.NewTicker(k.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-k.stop:
+			return
+		case <-ticker.C:
+			if err := k.renew(); errors.Is(err, ErrLeaseLost) {
+				log.Print(err.Error())
+				return
+			}
+		}
+	}
+}
+
+// renew renews the lease once. Errors other than ErrLeaseLost are retried
+// by the next heartbeat, as long as the lease has not expired
+func (k *nodeLeaseKeeper) renew() error {
+	ctx, cancel := context.WithTimeout(context.Background(), k.ttl/3)
+	defer cancel()
+
+	k.mu.Lock()
+	lease := k.lease
+	k.mu.Unlock()
+
+	start := k.elapsed()
+	renewed, err := k.coordinator.Renew(ctx, lease, k.ttl)
+
+	if errors.Is(err, ErrLeaseLost) {
+		k.expiry.Store(0)
+	}
+	if err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	k.lease = renewed
+	k.mu.Unlock()
+
+	// a lost lease must not be revived by a late renewal
+	expiry := k.expiry.Load()
+	if expiry != 0 {
+		k.expiry.CompareAndSwap(expiry, int64(start+k.ttl))
+	}
+	return nil
+}
+
+// check returns ErrLeaseLost if the lease was lost or has expired
+func (k *nodeLeaseKeeper) check() error {
+	expiry := k.expiry.Load()
+	if expiry == 0 {
+		return fmt.Errorf("%w: node %d", ErrLeaseLost, k.node)
+	}
+	if int64(k.elapsed()) >= expiry {
+		return fmt.Errorf("%w: node %d expired", ErrLeaseLost, k.node)
+	}
+	return nil
+}
+
+// elapsed returns the monotonic time since the keeper started
+func (k *nodeLeaseKeeper) elapsed() time.Duration {
+	return k.now().Sub(k.start)
+}
+
+// close stops renewing the lease and releases it
+func (k *nodeLeaseKeeper) close() error {
+	var err error
+	k.once.Do(func() {
+		close(k.stop)
+		<-k.done
+
+		k.mu.Lock()
+		lease := k.lease
+		k.mu.Unlock()
+
+		if k.expiry.Swap(0) == 0 {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), k.ttl)
+		defer cancel()
+		err = k.coordinator.Release(ctx, lease)
+	})
+	return err
+}
+
+// newLeaseOwner returns an owner which is unique to the factory
+func newLeaseOwner() string {
+	hostname, _ := os.Hostname()
+
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), hex.EncodeToString(suffix))
+}
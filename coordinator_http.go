@@ -0,0 +1,210 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// leaseRequest is the body of the requests to a coordinator server
+type leaseRequest struct {
+	NodeBits int32  `json:"node_bits,omitempty"`
+	Node     int32  `json:"node"`
+	Owner    string `json:"owner"`
+	TTL      int64  `json:"ttl_millis,omitempty"`
+}
+
+// leaseResponse is the body of the responses of a coordinator server.
+// The expiry is in unix millis
+type leaseResponse struct {
+	Node   int32  `json:"node"`
+	Owner  string `json:"owner"`
+	Expiry int64  `json:"expiry"`
+	Error  string `json:"error,omitempty"`
+}
+
+// nodeCoordinatorHandler serves a NodeCoordinator over HTTP
+type nodeCoordinatorHandler struct {
+	coordinator NodeCoordinator
+}
+
+// NewNodeCoordinatorHandler returns a handler which serves the coordinator
+// at POST /acquire, /renew and /release. Use http.StripPrefix to serve it
+// under a path
+func NewNodeCoordinatorHandler(coordinator NodeCoordinator) http.Handler {
+	return &nodeCoordinatorHandler{
+		coordinator: coordinator,
+	}
+}
+
+func (h *nodeCoordinatorHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeLeaseResponse(w, http.StatusMethodNotAllowed, leaseResponse{Error: "method not allowed"})
+		return
+	}
+
+	var request leaseRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeLeaseResponse(w, http.StatusBadRequest, leaseResponse{Error: err.Error()})
+		return
+	}
+
+	lease := NodeLease{Node: request.Node, Owner: request.Owner}
+	ttl := time.Duration(request.TTL) * time.Millisecond
+
+	if err := validateLeaseRequest(r.URL.Path, request.NodeBits, ttl); err != nil {
+		writeLeaseResponse(w, http.StatusBadRequest, leaseResponse{Error: err.Error()})
+		return
+	}
+
+	var err error
+	switch r.URL.Path {
+	case "/acquire":
+		lease, err = h.coordinator.Acquire(r.Context(), request.NodeBits, request.Owner, ttl)
+	case "/renew":
+		lease, err = h.coordinator.Renew(r.Context(), lease, ttl)
+	case "/release":
+		err = h.coordinator.Release(r.Context(), lease)
+	default:
+		writeLeaseResponse(w, http.StatusNotFound, leaseResponse{Error: "not found"})
+		return
+	}
+
+	switch {
+	case err == nil:
+		writeLeaseResponse(w, http.StatusOK, leaseResponse{
+			Node:   lease.Node,
+			Owner:  lease.Owner,
+			Expiry: lease.Expiry.UnixMilli(),
+		})
+	case errors.Is(err, ErrLeaseLost):
+		writeLeaseResponse(w, http.StatusConflict, leaseResponse{Error: err.Error()})
+	case errors.Is(err, ErrNodeUnavailable):
+		writeLeaseResponse(w, http.StatusServiceUnavailable, leaseResponse{Error: err.Error()})
+	case errors.Is(err, ErrInvalidLease):
+		writeLeaseResponse(w, http.StatusBadRequest, leaseResponse{Error: err.Error()})
+	default:
+		writeLeaseResponse(w, http.StatusInternalServerError, leaseResponse{Error: err.Error()})
+	}
+}
+
+// validateLeaseRequest checks the request before it reaches the
+// coordinator, which may not validate untrusted input
+func validateLeaseRequest(path string, nodeBits int32, ttl time.Duration) error {
+	switch path {
+	case "/acquire":
+		return validateLease(nodeBits, ttl)
+	case "/renew":
+		return validateLeaseTTL(ttl)
+	}
+	return nil
+}
+
+func writeLeaseResponse(w http.ResponseWriter, status int, response leaseResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+// httpNodeCoordinator is a NodeCoordinator client of a server created
+// using NewNodeCoordinatorHandler
+type httpNodeCoordinator struct {
+	url    string
+	client *http.Client
+}
+
+func NewHTTPNodeCoordinator(url string) *httpNodeCoordinator {
+	return NewHTTPNodeCoordinatorWithClient(url, http.DefaultClient)
+}
+
+func NewHTTPNodeCoordinatorWithClient(url string, client *http.Client) *httpNodeCoordinator {
+	return &httpNodeCoordinator{
+		url:    strings.TrimSuffix(url, "/"),
+		client: client,
+	}
+}
+
+func (c *httpNodeCoordinator) Acquire(ctx context.Context, nodeBits int32, owner string, ttl time.Duration) (NodeLease, error) {
+	return c.post(ctx, "/acquire", leaseRequest{NodeBits: nodeBits, Owner: owner, TTL: ttl.Milliseconds()})
+}
+
+func (c *httpNodeCoordinator) Renew(ctx context.Context, lease NodeLease, ttl time.Duration) (NodeLease, error) {
+	return c.post(ctx, "/renew", leaseRequest{Node: lease.Node, Owner: lease.Owner, TTL: ttl.Milliseconds()})
+}
+
+func (c *httpNodeCoordinator) Release(ctx context.Context, lease NodeLease) error {
+	_, err := c.post(ctx, "/release", leaseRequest{Node: lease.Node, Owner: lease.Owner})
+	return err
+}
+
+// post sends the request and maps the status of the response back to
+// ErrLeaseLost, ErrNodeUnavailable and ErrInvalidLease
+func (c *httpNodeCoordinator) post(ctx context.Context, path string, request leaseRequest) (NodeLease, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return NodeLease{}, err
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url+path, bytes.NewReader(body))
+	if err != nil {
+		return NodeLease{}, err
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+
+	httpResponse, err := c.client.Do(httpRequest)
+	if err != nil {
+		return NodeLease{}, err
+	}
+	defer httpResponse.Body.Close()
+
+	var response leaseResponse
+	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+		return NodeLease{}, fmt.Errorf("invalid node coordinator response: %w", err)
+	}
+
+	switch httpResponse.StatusCode {
+	case http.StatusOK:
+		return NodeLease{
+			Node:   response.Node,
+			Owner:  response.Owner,
+			Expiry: time.UnixMilli(response.Expiry),
+		}, nil
+	case http.StatusConflict:
+		return NodeLease{}, wrapLeaseError(ErrLeaseLost, response.Error)
+	case http.StatusServiceUnavailable:
+		return NodeLease{}, wrapLeaseError(ErrNodeUnavailable, response.Error)
+	case http.StatusBadRequest:
+		return NodeLease{}, wrapLeaseError(ErrInvalidLease, response.Error)
+	default:
+		return NodeLease{}, fmt.Errorf("node coordinator error %d: %s", httpResponse.StatusCode, response.Error)
+	}
+}
+
+// wrapLeaseError wraps the error message of the server, which already
+// starts with the message of the sentinel error
+func wrapLeaseError(sentinel error, message string) error {
+	message = strings.TrimPrefix(message, sentinel.Error()+": ")
+	return fmt.Errorf("%w: %s", sentinel, message)
+}
@@ -0,0 +1,348 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// settableClock is a clock which is safe for concurrent use
+type settableClock struct {
+	millis atomic.Int64
+}
+
+func newSettableClock() *settableClock {
+	clock := &settableClock{}
+	clock.millis.Store(time.Now().UnixMilli())
+	return clock
+}
+
+func (c *settableClock) UnixMilli() int64 {
+	return c.millis.Load()
+}
+
+func (c *settableClock) Advance(d time.Duration) {
+	c.millis.Add(d.Milliseconds())
+}
+
+func (c *settableClock) Now() time.Time {
+	return time.UnixMilli(c.millis.Load())
+}
+
+// unreachableCoordinator is a coordinator which can not renew leases
+type unreachableCoordinator struct {
+	NodeCoordinator
+}
+
+func (c unreachableCoordinator) Renew(ctx context.Context, lease NodeLease, ttl time.Duration) (NodeLease, error) {
+	return NodeLease{}, errors.New("coordinator unreachable")
+}
+
+func Test_MemoryNodeCoordinator(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should lease distinct nodes", func(t *testing.T) {
+		coordinator := NewMemoryNodeCoordinator()
+
+		first, err := coordinator.Acquire(ctx, 1, "a", time.Minute)
+		assert.Nil(t, err)
+		second, err := coordinator.Acquire(ctx, 1, "b", time.Minute)
+		assert.Nil(t, err)
+		assert.Equal(t, int32(0), first.Node)
+		assert.Equal(t, int32(1), second.Node)
+
+		_, err = coordinator.Acquire(ctx, 1, "c", time.Minute)
+		assert.True(t, errors.Is(err, ErrNodeUnavailable))
+
+		// released node can be acquired again
+		assert.Nil(t, coordinator.Release(ctx, first))
+		third, err := coordinator.Acquire(ctx, 1, "c", time.Minute)
+		assert.Nil(t, err)
+		assert.Equal(t, int32(0), third.Node)
+	})
+
+	t.Run("given invalid node bits or ttl should return error", func(t *testing.T) {
+		coordinator := NewMemoryNodeCoordinator()
+
+		for _, nodeBits := range []int32{-1, 21, 31, 32} {
+			_, err := coordinator.Acquire(ctx, nodeBits, "a", time.Minute)
+			assert.True(t, errors.Is(err, ErrInvalidLease), "node bits: %d", nodeBits)
+		}
+
+		for _, ttl := range []time.Duration{-time.Second, 0, 2 * time.Nanosecond, 999 * time.Microsecond} {
+			_, err := coordinator.Acquire(ctx, 1, "a", ttl)
+			assert.True(t, errors.Is(err, ErrInvalidLease), "ttl: %s", ttl)
+		}
+
+		lease, err := coordinator.Acquire(ctx, 1, "a", time.Minute)
+		assert.Nil(t, err)
+		_, err = coordinator.Renew(ctx, lease, -time.Second)
+		assert.True(t, errors.Is(err, ErrInvalidLease))
+		_, err = coordinator.Renew(ctx, lease, time.Microsecond)
+		assert.True(t, errors.Is(err, ErrInvalidLease))
+	})
+
+	t.Run("given ttl below a millisecond should not lease node", func(t *testing.T) {
+		server := httptest.NewServer(NewNodeCoordinatorHandler(NewMemoryNodeCoordinator()))
+		defer server.Close()
+
+		coordinators := []NodeCoordinator{NewMemoryNodeCoordinator(), NewHTTPNodeCoordinator(server.URL)}
+		for _, coordinator := range coordinators {
+			for _, ttl := range []time.Duration{2 * time.Nanosecond, 999 * time.Microsecond} {
+				tsidFactory, err := TsidFactoryBuilder().
+					WithNodeBits(NODE_BITS_1024).
+					WithNodeCoordinator(coordinator, ttl).
+					NewInstance()
+				assert.Nil(t, tsidFactory)
+				assert.True(t, errors.Is(err, ErrInvalidLease), "ttl: %s", ttl)
+			}
+		}
+	})
+
+	t.Run("given expired lease should lease node to another owner", func(t *testing.T) {
+		clock := newSettableClock()
+		coordinator := NewMemoryNodeCoordinator()
+		coordinator.clock = clock
+
+		lease, err := coordinator.Acquire(ctx, 1, "a", time.Second)
+		assert.Nil(t, err)
+
+		clock.Advance(500 * time.Millisecond)
+		renewed, err := coordinator.Renew(ctx, lease, time.Second)
+		assert.Nil(t, err)
+		assert.True(t, renewed.Expiry.After(lease.Expiry))
+
+		clock.Advance(time.Second)
+		other, err := coordinator.Acquire(ctx, 1, "b", time.Second)
+		assert.Nil(t, err)
+		assert.Equal(t, lease.Node, other.Node)
+
+		_, err = coordinator.Renew(ctx, lease, time.Second)
+		assert.True(t, errors.Is(err, ErrLeaseLost))
+
+		// release by the previous owner does not free the node
+		assert.Nil(t, coordinator.Release(ctx, lease))
+		_, err = coordinator.Renew(ctx, other, time.Second)
+		assert.Nil(t, err)
+	})
+}
+
+func Test_WithNodeCoordinator(t *testing.T) {
+
+	newFactory := func(coordinator NodeCoordinator, clock Clock) (*TsidFactory, error) {
+		return TsidFactoryBuilder().
+			WithNodeBits(NODE_BITS_1024).
+			WithClock(clock).
+			WithNodeCoordinator(coordinator, time.Minute).
+			NewInstance()
+	}
+
+	t.Run("should lease node of factory", func(t *testing.T) {
+		coordinator := NewMemoryNodeCoordinator()
+
+		first, err := newFactory(coordinator, NewSystemClock())
+		assert.Nil(t, err)
+		second, err := newFactory(coordinator, NewSystemClock())
+		assert.Nil(t, err)
+
+		tsid, err := second.Generate()
+		assert.Nil(t, err)
+		assert.Equal(t, int32(1), second.Decode(tsid).Node)
+
+		// closed factory releases its node
+		assert.Nil(t, first.Close())
+		_, err = first.Generate()
+		assert.True(t, errors.Is(err, ErrLeaseLost))
+
+		third, err := newFactory(coordinator, NewSystemClock())
+		assert.Nil(t, err)
+		defer third.Close()
+
+		tsid, err = third.Generate()
+		assert.Nil(t, err)
+		assert.Equal(t, int32(0), third.Decode(tsid).Node)
+		assert.Nil(t, second.Close())
+	})
+
+	t.Run("given node should not lease node", func(t *testing.T) {
+		tsidFactory, err := TsidFactoryBuilder().
+			WithNodeBits(NODE_BITS_1024).
+			WithNode(7).
+			WithNodeCoordinator(NewMemoryNodeCoordinator(), time.Minute).
+			NewInstance()
+		assert.Nil(t, err)
+		assert.Nil(t, tsidFactory.lease)
+
+		tsid, err := tsidFactory.Generate()
+		assert.Nil(t, err)
+		assert.Equal(t, int32(7), tsidFactory.Decode(tsid).Node)
+	})
+
+	t.Run("given lease taken over should stop generating", func(t *testing.T) {
+		clock := newSettableClock()
+		coordinator := NewMemoryNodeCoordinator()
+		coordinator.clock = clock
+
+		tsidFactory, err := newFactory(coordinator, NewSystemClock())
+		assert.Nil(t, err)
+		defer tsidFactory.Close()
+
+		clock.Advance(2 * time.Minute)
+		_, err = coordinator.Acquire(context.Background(), NODE_BITS_1024, "other", time.Minute)
+		assert.Nil(t, err)
+
+		err = tsidFactory.lease.renew()
+		assert.True(t, errors.Is(err, ErrLeaseLost))
+
+		_, err = tsidFactory.Generate()
+		assert.True(t, errors.Is(err, ErrLeaseLost))
+	})
+
+	t.Run("given lease not renewed in time should stop generating", func(t *testing.T) {
+		leaseClock := newSettableClock()
+		lease, err := acquireNodeLease(NewMemoryNodeCoordinator(), leaseClock.Now, NODE_BITS_1024, time.Minute)
+		assert.Nil(t, err)
+
+		// the clock of the factory is frozen, the lease expires anyway
+		tsidFactory, err := TsidFactoryBuilder().
+			WithNodeBits(NODE_BITS_1024).
+			WithNode(lease.node).
+			WithClock(time.Now()).
+			NewInstance()
+		assert.Nil(t, err)
+		tsidFactory.lease = lease
+		defer tsidFactory.Close()
+
+		leaseClock.Advance(59 * time.Second)
+		_, err = tsidFactory.Generate()
+		assert.Nil(t, err)
+
+		leaseClock.Advance(time.Second)
+		_, err = tsidFactory.Generate()
+		assert.True(t, errors.Is(err, ErrLeaseLost))
+	})
+
+	t.Run("given frozen clock lease should expire", func(t *testing.T) {
+		tsidFactory, err := TsidFactoryBuilder().
+			WithNodeBits(NODE_BITS_1024).
+			WithClock(time.Now()).
+			WithNodeCoordinator(unreachableCoordinator{NewMemoryNodeCoordinator()}, 50*time.Millisecond).
+			NewInstance()
+		assert.Nil(t, err)
+		defer tsidFactory.Close()
+
+		_, err = tsidFactory.Generate()
+		assert.Nil(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+		_, err = tsidFactory.Generate()
+		assert.True(t, errors.Is(err, ErrLeaseLost))
+	})
+
+	t.Run("should renew lease in background", func(t *testing.T) {
+		tsidFactory, err := TsidFactoryBuilder().
+			WithNodeBits(NODE_BITS_1024).
+			WithNodeCoordinator(NewMemoryNodeCoordinator(), 150*time.Millisecond).
+			NewInstance()
+		assert.Nil(t, err)
+		defer tsidFactory.Close()
+
+		time.Sleep(400 * time.Millisecond)
+		_, err = tsidFactory.Generate()
+		assert.Nil(t, err)
+	})
+}
+
+func Test_HTTPNodeCoordinator(t *testing.T) {
+	ctx := context.Background()
+
+	server := httptest.NewServer(NewNodeCoordinatorHandler(NewMemoryNodeCoordinator()))
+	defer server.Close()
+
+	coordinator := NewHTTPNodeCoordinator(server.URL)
+
+	t.Run("should lease nodes over http", func(t *testing.T) {
+		first, err := coordinator.Acquire(ctx, 1, "a", time.Minute)
+		assert.Nil(t, err)
+		assert.Equal(t, int32(0), first.Node)
+		assert.Equal(t, "a", first.Owner)
+		assert.True(t, first.Expiry.After(time.Now()))
+
+		second, err := coordinator.Acquire(ctx, 1, "b", time.Minute)
+		assert.Nil(t, err)
+		assert.Equal(t, int32(1), second.Node)
+
+		_, err = coordinator.Acquire(ctx, 1, "c", time.Minute)
+		assert.True(t, errors.Is(err, ErrNodeUnavailable))
+
+		_, err = coordinator.Renew(ctx, first, time.Minute)
+		assert.Nil(t, err)
+
+		assert.Nil(t, coordinator.Release(ctx, first))
+		assert.Nil(t, coordinator.Release(ctx, second))
+
+		_, err = coordinator.Renew(ctx, first, time.Minute)
+		assert.True(t, errors.Is(err, ErrLeaseLost))
+		assert.Equal(t, "node lease lost: node 0", err.Error())
+	})
+
+	t.Run("given invalid request should return bad request", func(t *testing.T) {
+		for _, body := range []string{
+			`{"node_bits":-1,"owner":"a","ttl_millis":1000}`,
+			`{"node_bits":32,"owner":"a","ttl_millis":1000}`,
+			`{"node_bits":1,"owner":"a","ttl_millis":0}`,
+		} {
+			response, err := http.Post(server.URL+"/acquire", "application/json", strings.NewReader(body))
+			assert.Nil(t, err)
+			response.Body.Close()
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode, body)
+		}
+
+		_, err := coordinator.Acquire(ctx, -1, "a", time.Minute)
+		assert.True(t, errors.Is(err, ErrInvalidLease))
+		assert.Equal(t, "invalid node lease request: node bits out of range [0, 20]: -1", err.Error())
+
+		_, err = coordinator.Renew(ctx, NodeLease{Owner: "a"}, 0)
+		assert.True(t, errors.Is(err, ErrInvalidLease))
+	})
+
+	t.Run("should lease node of factory over http", func(t *testing.T) {
+		tsidFactory, err := TsidFactoryBuilder().
+			WithNodeBits(NODE_BITS_1024).
+			WithNodeCoordinator(coordinator, time.Minute).
+			NewInstance()
+		assert.Nil(t, err)
+
+		_, err = tsidFactory.Generate()
+		assert.Nil(t, err)
+		assert.Nil(t, tsidFactory.Close())
+
+		// the node was released
+		lease, err := coordinator.Acquire(ctx, NODE_BITS_1024, "a", time.Minute)
+		assert.Nil(t, err)
+		assert.Equal(t, int32(0), lease.Node)
+	})
+}
@@ -0,0 +1,69 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"fmt"
+	"time"
+)
+
+// Components contains the decoded parts of a tsid
+type Components struct {
+	Time    time.Time // time of creation
+	Node    int32     // node id of the factory which generated the tsid
+	Counter int32     // counter value within the time unit
+	Epoch   int64     // epoch in unix millis used to decode the time
+}
+
+// Decode splits the tsid into its components using the default layout
+// with the given node bits and epoch. Node bits must be in range [0, 20]
+func Decode(t Tsid, nodeBits int32, epoch int64) (Components, error) {
+	if nodeBits < 0 || nodeBits > 20 {
+		return Components{}, fmt.Errorf("node bits out of range [0, 20]: %d", nodeBits)
+	}
+
+	layout := DefaultLayout
+	layout.NodeBits = nodeBits
+	layout.CounterBits = RANDOM_BITS - nodeBits
+
+	return decode(t, layout, epoch), nil
+}
+
+// Decode splits the tsid into its components using the layout and epoch
+// of the factory
+func (factory *TsidFactory) Decode(t Tsid) Components {
+	return decode(t, factory.layout, factory.customEpoch)
+}
+
+func decode(t Tsid, layout Layout, epoch int64) Components {
+	return Components{
+		Time:    time.UnixMilli(layout.GetUnixMillis(t, epoch)).UTC(),
+		Node:    layout.GetNode(t),
+		Counter: layout.GetCounter(t),
+		Epoch:   epoch,
+	}
+}
+
+// GetInstant returns time of creation
+func (t Tsid) GetInstant() time.Time {
+	return time.UnixMilli(t.GetUnixMillis()).UTC()
+}
+
+// GetInstantWithCustomEpoch returns time of creation using the given epoch
+func (t Tsid) GetInstantWithCustomEpoch(epoch int64) time.Time {
+	return time.UnixMilli(t.GetUnixMillisWithCustomEpoch(epoch)).UTC()
+}
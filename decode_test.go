@@ -0,0 +1,78 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Decode(t *testing.T) {
+
+	t.Run("given factory should decode node and counter", func(t *testing.T) {
+		now := time.Now()
+		epoch := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+
+		intRandom := NewIntRandomWithSupplierFunc(func() (int32, error) {
+			return 100, nil
+		})
+
+		tsidFactory, err := TsidFactoryBuilder().
+			WithNodeBits(NODE_BITS_1024).
+			WithNode(777).
+			WithCustomEpoch(epoch).
+			WithClock(now).
+			WithRandom(intRandom).
+			NewInstance()
+		assert.Nil(t, err)
+
+		tsid, err := tsidFactory.Generate()
+		assert.Nil(t, err)
+
+		expected := Components{
+			Time:    time.UnixMilli(now.UnixMilli()).UTC(),
+			Node:    777,
+			Counter: 101,
+			Epoch:   epoch,
+		}
+		assert.Equal(t, expected, tsidFactory.Decode(tsid))
+
+		components, err := Decode(tsid, NODE_BITS_1024, epoch)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, components)
+	})
+
+	t.Run("given invalid node bits should return error", func(t *testing.T) {
+		_, err := Decode(Fast(), 21, TSID_EPOCH)
+		assert.NotNil(t, err)
+
+		_, err = Decode(Fast(), -1, TSID_EPOCH)
+		assert.NotNil(t, err)
+	})
+}
+
+func Test_GetInstant(t *testing.T) {
+
+	t.Run("should return time of creation", func(t *testing.T) {
+		tsid := Fast()
+
+		assert.Equal(t, tsid.GetUnixMillis(), tsid.GetInstant().UnixMilli())
+		assert.Equal(t, tsid.GetUnixMillisWithCustomEpoch(0), tsid.GetInstantWithCustomEpoch(0).UnixMilli())
+	})
+}
@@ -0,0 +1,67 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import "fmt"
+
+// String returns the canonical string representation of the tsid
+func (t Tsid) String() string {
+	return t.ToString()
+}
+
+// Format implements fmt.Formatter. The verbs %v, %s and %q format the
+// canonical string, while %d, %x, %X, %o and %b format the number.
+func (t Tsid) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'v', 's', 'q':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), t.ToString())
+	case 'd', 'x', 'X', 'o', 'b':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), t.ToNumber())
+	default:
+		fmt.Fprintf(f, "%%!%c(tsid.Tsid=%s)", verb, t.ToString())
+	}
+}
+
+// MarshalText encodes the tsid as canonical string
+func (t Tsid) MarshalText() ([]byte, error) {
+	return []byte(t.ToString()), nil
+}
+
+// UnmarshalText decodes the tsid from canonical string
+func (t *Tsid) UnmarshalText(text []byte) error {
+	tsid, err := FromString(string(text))
+	if err != nil {
+		return err
+	}
+	*t = tsid
+	return nil
+}
+
+// MarshalBinary encodes the tsid as 8 bytes in big-endian order
+func (t Tsid) MarshalBinary() ([]byte, error) {
+	return t.ToBytes(), nil
+}
+
+// UnmarshalBinary decodes the tsid from 8 bytes in big-endian order
+func (t *Tsid) UnmarshalBinary(data []byte) error {
+	tsid, err := FromBytes(data)
+	if err != nil {
+		return err
+	}
+	*t = tsid
+	return nil
+}
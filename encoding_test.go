@@ -0,0 +1,123 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Format(t *testing.T) {
+
+	t.Run("should format tsid using verbs", func(t *testing.T) {
+		tsid := Fast()
+		number := tsid.ToNumber()
+
+		assert.Equal(t, tsid.ToString(), tsid.String())
+		assert.Equal(t, tsid.ToString(), fmt.Sprint(tsid))
+		assert.Equal(t, tsid.ToString(), fmt.Sprintf("%v", tsid))
+		assert.Equal(t, tsid.ToString(), fmt.Sprintf("%s", tsid))
+		assert.Equal(t, strconv.Quote(tsid.ToString()), fmt.Sprintf("%q", tsid))
+		assert.Equal(t, strconv.FormatInt(number, 10), fmt.Sprintf("%d", tsid))
+		assert.Equal(t, fmt.Sprintf("%x", number), fmt.Sprintf("%x", tsid))
+		assert.Equal(t, fmt.Sprintf("%020X", number), fmt.Sprintf("%020X", tsid))
+		assert.Equal(t, tsid.ToString(), fmt.Sprintf("%v", &tsid))
+	})
+}
+
+func Test_MarshalText(t *testing.T) {
+
+	t.Run("should round trip canonical string", func(t *testing.T) {
+		expected := Fast()
+
+		text, err := expected.MarshalText()
+		assert.Nil(t, err)
+		assert.Equal(t, expected.ToString(), string(text))
+
+		var tsid Tsid
+		assert.Nil(t, tsid.UnmarshalText(text))
+		assert.Equal(t, expected, tsid)
+	})
+
+	t.Run("given invalid text should return error", func(t *testing.T) {
+		var tsid Tsid
+		err := tsid.UnmarshalText([]byte("invalid"))
+		assert.True(t, errors.Is(err, ErrInvalidLength))
+	})
+
+	t.Run("should be usable as JSON map key", func(t *testing.T) {
+		expected := map[Tsid]int{Fast(): 1}
+
+		data, err := json.Marshal(expected)
+		assert.Nil(t, err)
+
+		actual := map[Tsid]int{}
+		assert.Nil(t, json.Unmarshal(data, &actual))
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("should be usable as flag value", func(t *testing.T) {
+		expected := Fast()
+
+		var tsid Tsid
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		flags.TextVar(&tsid, "id", Tsid{}, "tsid")
+
+		assert.Nil(t, flags.Parse([]string{"-id", expected.ToString()}))
+		assert.Equal(t, expected, tsid)
+	})
+}
+
+func Test_MarshalBinary(t *testing.T) {
+
+	t.Run("should round trip bytes", func(t *testing.T) {
+		expected := Fast()
+
+		data, err := expected.MarshalBinary()
+		assert.Nil(t, err)
+		assert.Equal(t, expected.ToBytes(), data)
+
+		var tsid Tsid
+		assert.Nil(t, tsid.UnmarshalBinary(data))
+		assert.Equal(t, expected, tsid)
+	})
+
+	t.Run("given invalid bytes should return error", func(t *testing.T) {
+		var tsid Tsid
+		err := tsid.UnmarshalBinary([]byte{1, 2, 3})
+		assert.True(t, errors.Is(err, ErrInvalidLength))
+	})
+
+	t.Run("should be encodable using gob", func(t *testing.T) {
+		expected := Fast()
+
+		buffer := &bytes.Buffer{}
+		assert.Nil(t, gob.NewEncoder(buffer).Encode(expected))
+
+		var tsid Tsid
+		assert.Nil(t, gob.NewDecoder(buffer).Decode(&tsid))
+		assert.Equal(t, expected, tsid)
+	})
+}
@@ -0,0 +1,144 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// Encoding converts tsids to and from fixed width strings. The built-in
+// encodings use alphabets in ASCII order, so that the strings sort like
+// the tsids
+type Encoding interface {
+	Encode(t Tsid) (string, error)
+	Decode(str string) (Tsid, error)
+}
+
+var (
+	// Base32Encoding is the canonical encoding of ToString, 13 characters
+	// of Crockford's base32
+	Base32Encoding Encoding = base32Encoding{}
+
+	// Base62Encoding uses digits, upper and lower case letters, 11 characters
+	Base62Encoding Encoding = newBaseEncoding("0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz", 11, math.MaxUint64)
+
+	// Base58Encoding uses the Bitcoin alphabet, 11 characters
+	Base58Encoding Encoding = newBaseEncoding("123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz", 11, math.MaxUint64)
+
+	// HexEncoding uses lower case hexadecimal digits, 16 characters
+	HexEncoding Encoding = newBaseEncoding("0123456789abcdef", 16, math.MaxUint64)
+
+	// DecimalEncoding uses zero-padded decimal digits of ToNumber, 19
+	// characters. Tsids with the sign bit set do not fit and return
+	// ErrEncodingOverflow
+	DecimalEncoding Encoding = newBaseEncoding("0123456789", 19, math.MaxInt64)
+)
+
+// ToStringWithEncoding converts the tsid to a string using the encoding
+func (t Tsid) ToStringWithEncoding(encoding Encoding) (string, error) {
+	return encoding.Encode(t)
+}
+
+// FromStringWithEncoding returns the tsid decoded from the string using
+// the encoding. It returns an error wrapping one of the parsing errors
+// if the string is not valid
+func FromStringWithEncoding(str string, encoding Encoding) (Tsid, error) {
+	return encoding.Decode(str)
+}
+
+// base32Encoding adapts ToString and FromString to Encoding
+type base32Encoding struct{}
+
+func (base32Encoding) Encode(t Tsid) (string, error) {
+	return t.ToString(), nil
+}
+
+func (base32Encoding) Decode(str string) (Tsid, error) {
+	return FromString(str)
+}
+
+// baseEncoding encodes the unsigned number of the tsid as width digits
+// of the alphabet, most significant first
+type baseEncoding struct {
+	alphabet string
+	values   [128]int8
+	width    int
+	max      uint64
+}
+
+func newBaseEncoding(alphabet string, width int, max uint64) *baseEncoding {
+	encoding := &baseEncoding{
+		alphabet: alphabet,
+		width:    width,
+		max:      max,
+	}
+
+	for i := range encoding.values {
+		encoding.values[i] = -1
+	}
+	for i := 0; i < len(alphabet); i++ {
+		encoding.values[alphabet[i]] = int8(i)
+	}
+	return encoding
+}
+
+func (e *baseEncoding) Encode(t Tsid) (string, error) {
+	number := uint64(t.number)
+	if number > e.max {
+		return "", fmt.Errorf("%w: %d does not fit in %d characters", ErrEncodingOverflow, number, e.width)
+	}
+
+	base := uint64(len(e.alphabet))
+	chars := make([]byte, e.width)
+	for i := e.width - 1; i >= 0; i-- {
+		chars[i] = e.alphabet[number%base]
+		number /= base
+	}
+	return string(chars), nil
+}
+
+func (e *baseEncoding) Decode(str string) (Tsid, error) {
+	if len(str) != e.width {
+		return Nil, fmt.Errorf("%w: expected %d characters, got %d", ErrInvalidLength, e.width, len(str))
+	}
+
+	base := uint64(len(e.alphabet))
+	var number uint64
+	for i := 0; i < len(str); i++ {
+		c := str[i]
+		if c >= 128 {
+			return Nil, fmt.Errorf("%w: %q at position %d", ErrNonASCII, c, i)
+		}
+		if e.values[c] == -1 {
+			return Nil, fmt.Errorf("%w: %q at position %d", ErrInvalidCharacter, c, i)
+		}
+
+		hi, lo := bits.Mul64(number, base)
+		lo, carry := bits.Add64(lo, uint64(e.values[c]), 0)
+		if hi != 0 || carry != 0 {
+			return Nil, fmt.Errorf("%w: %q", ErrEncodingOverflow, str)
+		}
+		number = lo
+	}
+
+	if number > e.max {
+		return Nil, fmt.Errorf("%w: %q", ErrEncodingOverflow, str)
+	}
+	return NewTsid(int64(number)), nil
+}
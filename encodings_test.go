@@ -0,0 +1,117 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var encodings = map[string]Encoding{
+	"base32":  Base32Encoding,
+	"base62":  Base62Encoding,
+	"base58":  Base58Encoding,
+	"hex":     HexEncoding,
+	"decimal": DecimalEncoding,
+}
+
+func Test_Encodings(t *testing.T) {
+
+	t.Run("should encode to fixed width", func(t *testing.T) {
+		cases := []struct {
+			encoding Encoding
+			tsid     Tsid
+			expected string
+		}{
+			{Base62Encoding, NewTsid(0), "00000000000"},
+			{Base62Encoding, NewTsid(61), "0000000000z"},
+			{Base62Encoding, NewTsid(-1), "LygHa16AHYF"},
+			{Base58Encoding, NewTsid(0), "11111111111"},
+			{Base58Encoding, NewTsid(-1), "jpXCZedGfVQ"},
+			{HexEncoding, NewTsid(255), "00000000000000ff"},
+			{HexEncoding, NewTsid(-1), "ffffffffffffffff"},
+			{DecimalEncoding, NewTsid(1), "0000000000000000001"},
+			{DecimalEncoding, NewTsid(9223372036854775807), "9223372036854775807"},
+		}
+
+		for _, c := range cases {
+			str, err := c.tsid.ToStringWithEncoding(c.encoding)
+			assert.Nil(t, err)
+			assert.Equal(t, c.expected, str)
+
+			tsid, err := FromStringWithEncoding(str, c.encoding)
+			assert.Nil(t, err)
+			assert.Equal(t, c.tsid, tsid)
+		}
+	})
+
+	for name, encoding := range encodings {
+		encoding := encoding
+
+		t.Run(name+" should round trip and preserve order", func(t *testing.T) {
+			tsids := make([]Tsid, 1000)
+			for i := range tsids {
+				tsids[i] = Fast()
+			}
+			Sort(tsids)
+
+			prev := ""
+			for _, tsid := range tsids {
+				str, err := encoding.Encode(tsid)
+				assert.Nil(t, err)
+				assert.LessOrEqual(t, prev, str)
+				prev = str
+
+				decoded, err := encoding.Decode(str)
+				assert.Nil(t, err)
+				assert.Equal(t, tsid, decoded)
+			}
+		})
+	}
+
+	t.Run("given invalid string should return error", func(t *testing.T) {
+		cases := []struct {
+			encoding Encoding
+			str      string
+			err      error
+		}{
+			{Base62Encoding, "0000000000", ErrInvalidLength},
+			{Base62Encoding, "0000000000-", ErrInvalidCharacter},
+			{Base62Encoding, "LygHa16AHYG", ErrEncodingOverflow},
+			{Base62Encoding, "zzzzzzzzzzz", ErrEncodingOverflow},
+			{Base58Encoding, "1111111111O", ErrInvalidCharacter},
+			{Base58Encoding, "jpXCZedGfVR", ErrEncodingOverflow},
+			{HexEncoding, "00000000000000fg", ErrInvalidCharacter},
+			{HexEncoding, "00000000000000é", ErrNonASCII},
+			{DecimalEncoding, "9223372036854775808", ErrEncodingOverflow},
+			{DecimalEncoding, "000000000000000000a", ErrInvalidCharacter},
+		}
+
+		for _, c := range cases {
+			_, err := FromStringWithEncoding(c.str, c.encoding)
+			assert.True(t, errors.Is(err, c.err), "%s: %v", c.str, err)
+		}
+	})
+
+	t.Run("given tsid with sign bit should not encode decimal", func(t *testing.T) {
+		_, err := NewTsid(-1).ToStringWithEncoding(DecimalEncoding)
+		assert.True(t, errors.Is(err, ErrEncodingOverflow))
+		assert.Contains(t, err.Error(), "value does not fit in encoding width")
+	})
+}
@@ -0,0 +1,62 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import "errors"
+
+// Parsing errors. Errors returned by FromString, FromBytes, ParseUUID,
+// ParseULID and the encodings wrap one of these, so callers can match
+// them using errors.Is
+var (
+	ErrInvalidLength    = errors.New("invalid tsid length")
+	ErrInvalidCharacter = errors.New("invalid tsid character")
+	ErrOverflow         = errors.New("tsid first character out of range")
+	ErrEncodingOverflow = errors.New("value does not fit in encoding width")
+	ErrNonASCII         = errors.New("non-ascii character in tsid")
+	ErrInvalidUUID      = errors.New("invalid uuid")
+	ErrInvalidULID      = errors.New("invalid ulid")
+	ErrULIDPrecision    = errors.New("ulid does not fit in tsid")
+)
+
+// Prefix errors
+var (
+	ErrInvalidPrefix       = errors.New("invalid tsid prefix")
+	ErrPrefixMismatch      = errors.New("tsid prefix mismatch")
+	ErrPrefixRegistered    = errors.New("tsid prefix already registered")
+	ErrPrefixNotRegistered = errors.New("tsid prefix not registered")
+)
+
+// Generation errors
+var (
+	ErrClockMovedBackwards = errors.New("clock moved backwards")
+	ErrInvalidLayout       = errors.New("invalid layout")
+)
+
+// Time range errors
+var (
+	ErrTimeBeforeEpoch = errors.New("time before epoch")
+	ErrTimeOutOfRange  = errors.New("time out of range")
+)
+
+// Node errors
+var (
+	ErrNodeNotResolved = errors.New("node id not resolved")
+	ErrNodeOutOfRange  = errors.New("node id out of range")
+	ErrNodeUnavailable = errors.New("no node id available")
+	ErrLeaseLost       = errors.New("node lease lost")
+	ErrInvalidLease    = errors.New("invalid node lease request")
+)
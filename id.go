@@ -0,0 +1,112 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import "context"
+
+// ID is a tsid typed with the entity it identifies, so that the ids of
+// different entities can not be mixed up:
+//
+//	type User struct {
+//		Id tsid.ID[User]
+//	}
+//
+// It parses, formats, marshals and scans like Tsid
+type ID[T any] struct {
+	Tsid
+}
+
+// NewID returns the tsid typed with the entity T
+func NewID[T any](tsid Tsid) ID[T] {
+	return ID[T]{Tsid: tsid}
+}
+
+// ParseID returns the id of the entity T from the canonical string
+func ParseID[T any](str string) (ID[T], error) {
+	tsid, err := FromString(str)
+	if err != nil {
+		return ID[T]{}, err
+	}
+	return NewID[T](tsid), nil
+}
+
+// Compare returns -1 if the id is less than the other, 0 if equal and
+// +1 if greater
+func (id ID[T]) Compare(other ID[T]) int {
+	return id.Tsid.Compare(other.Tsid)
+}
+
+// Before checks if the id is less than the other
+func (id ID[T]) Before(other ID[T]) bool {
+	return id.Tsid.Before(other.Tsid)
+}
+
+// After checks if the id is greater than the other
+func (id ID[T]) After(other ID[T]) bool {
+	return id.Tsid.After(other.Tsid)
+}
+
+// Equal checks if both the ids are equal
+func (id ID[T]) Equal(other ID[T]) bool {
+	return id.Tsid.Equal(other.Tsid)
+}
+
+// TypedFactory generates ids of the entity T using a TsidFactory
+type TypedFactory[T any] struct {
+	factory *TsidFactory
+}
+
+// NewTypedFactory returns a factory of ids of the entity T. The tsid
+// factory can be shared between typed factories
+func NewTypedFactory[T any](factory *TsidFactory) *TypedFactory[T] {
+	return &TypedFactory[T]{
+		factory: factory,
+	}
+}
+
+// Generate returns a new id of the entity T
+func (f *TypedFactory[T]) Generate() (ID[T], error) {
+	return f.GenerateContext(context.Background())
+}
+
+// GenerateContext returns a new id like TsidFactory.GenerateContext
+func (f *TypedFactory[T]) GenerateContext(ctx context.Context) (ID[T], error) {
+	tsid, err := f.factory.GenerateContext(ctx)
+	if err != nil {
+		return ID[T]{}, err
+	}
+	return NewID[T](tsid), nil
+}
+
+// GenerateN returns n strictly increasing ids like TsidFactory.GenerateN
+func (f *TypedFactory[T]) GenerateN(n int) ([]ID[T], error) {
+	tsids, err := f.factory.GenerateN(n)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]ID[T], len(tsids))
+	for i, tsid := range tsids {
+		ids[i] = NewID[T](tsid)
+	}
+	return ids, nil
+}
+
+// Factory returns the underlying tsid factory
+func (f *TypedFactory[T]) Factory() *TsidFactory {
+	return f.factory
+}
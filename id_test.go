@@ -0,0 +1,115 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testUser struct {
+	Id   ID[testUser] `json:"id"`
+	Name string       `json:"name"`
+}
+
+type testOrder struct {
+	Id     ID[testOrder] `json:"id"`
+	UserId ID[testUser]  `json:"user_id"`
+}
+
+func Test_ID(t *testing.T) {
+	tsid, _ := FromString("01226N0640J7K")
+
+	t.Run("should parse and format like tsid", func(t *testing.T) {
+		id, err := ParseID[testUser]("01226N0640J7K")
+		assert.Nil(t, err)
+		assert.Equal(t, NewID[testUser](tsid), id)
+		assert.Equal(t, "01226N0640J7K", id.ToString())
+		assert.Equal(t, "01226N0640J7K", fmt.Sprint(id))
+		assert.Equal(t, fmt.Sprintf("%d", tsid), fmt.Sprintf("%d", id))
+
+		_, err = ParseID[testUser]("01226N0640J7")
+		assert.True(t, errors.Is(err, ErrInvalidLength))
+	})
+
+	t.Run("should compare ids", func(t *testing.T) {
+		a, b := NewID[testUser](NewTsid(1)), NewID[testUser](NewTsid(2))
+
+		assert.Equal(t, -1, a.Compare(b))
+		assert.True(t, a.Before(b))
+		assert.True(t, b.After(a))
+		assert.True(t, a.Equal(NewID[testUser](NewTsid(1))))
+		assert.True(t, a == NewID[testUser](NewTsid(1)))
+		assert.True(t, ID[testUser]{}.IsZero())
+	})
+
+	t.Run("should marshal json like tsid", func(t *testing.T) {
+		order := testOrder{Id: NewID[testOrder](tsid), UserId: NewID[testUser](NewTsid(42))}
+
+		data, err := json.Marshal(order)
+		assert.Nil(t, err)
+		assert.Equal(t, `{"id":"01226N0640J7K","user_id":"000000000001A"}`, string(data))
+
+		var decoded testOrder
+		assert.Nil(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, order, decoded)
+
+		// numbers are accepted like tsid
+		assert.Nil(t, json.Unmarshal([]byte(`{"id":42}`), &decoded))
+		assert.Equal(t, NewID[testOrder](NewTsid(42)), decoded.Id)
+	})
+
+	t.Run("should scan and value like tsid", func(t *testing.T) {
+		id := NewID[testUser](tsid)
+
+		value, err := id.Value()
+		assert.Nil(t, err)
+		assert.Equal(t, tsid.ToNumber(), value)
+
+		var scanned ID[testUser]
+		for _, src := range []any{tsid.ToNumber(), tsid.ToBytes(), tsid.ToString()} {
+			assert.Nil(t, scanned.Scan(src))
+			assert.Equal(t, id, scanned)
+		}
+	})
+}
+
+func Test_TypedFactory(t *testing.T) {
+	tsidFactory, err := TsidFactoryBuilder().NewInstance()
+	assert.Nil(t, err)
+
+	users := NewTypedFactory[testUser](tsidFactory)
+	orders := NewTypedFactory[testOrder](tsidFactory)
+	assert.Equal(t, tsidFactory, users.Factory())
+
+	user, err := users.Generate()
+	assert.Nil(t, err)
+	order, err := orders.Generate()
+	assert.Nil(t, err)
+	assert.True(t, order.Tsid.After(user.Tsid))
+
+	ids, err := users.GenerateN(100)
+	assert.Nil(t, err)
+	assert.Equal(t, 100, len(ids))
+	for i := 1; i < len(ids); i++ {
+		assert.True(t, ids[i].After(ids[i-1]))
+	}
+}
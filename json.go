@@ -0,0 +1,75 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+var jsonNull = []byte("null")
+
+// MarshalJSON encodes the tsid as a JSON string using the canonical
+// 13 characters representation. Strings are used by default as javascript
+// clients lose precision on 64 bit integers
+func (t Tsid) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.ToString())
+}
+
+// UnmarshalJSON decodes the tsid from either a JSON string in canonical
+// format or a JSON number. A JSON null leaves the tsid unchanged
+func (t *Tsid) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, jsonNull) {
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return err
+		}
+
+		tsid, err := FromString(str)
+		if err != nil {
+			return err
+		}
+		*t = tsid
+		return nil
+	}
+
+	number, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid tsid json value %s: %w", data, err)
+	}
+	*t = NewTsid(number)
+	return nil
+}
+
+// NumericTsid wraps a tsid so that it is encoded as a JSON number instead
+// of a string. It should only be used for consumers that can handle 64 bit
+// integers without losing precision. Decoding accepts both forms.
+type NumericTsid struct {
+	Tsid
+}
+
+// MarshalJSON encodes the tsid as a JSON number
+func (n NumericTsid) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatInt(n.ToNumber(), 10)), nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MarshalJSON(t *testing.T) {
+
+	t.Run("should encode tsid as canonical string", func(t *testing.T) {
+		tsid := Fast()
+
+		data, err := json.Marshal(tsid)
+		assert.Nil(t, err)
+		assert.Equal(t, `"`+tsid.ToString()+`"`, string(data))
+	})
+
+	t.Run("should encode tsid field of a struct as canonical string", func(t *testing.T) {
+		tsid := Fast()
+		value := struct {
+			Id Tsid `json:"id"`
+		}{Id: tsid}
+
+		data, err := json.Marshal(value)
+		assert.Nil(t, err)
+		assert.Equal(t, `{"id":"`+tsid.ToString()+`"}`, string(data))
+	})
+
+	t.Run("given numeric tsid should encode tsid as number", func(t *testing.T) {
+		tsid := Fast()
+		value := struct {
+			Id NumericTsid `json:"id"`
+		}{Id: NumericTsid{tsid}}
+
+		data, err := json.Marshal(value)
+		assert.Nil(t, err)
+		assert.Equal(t, `{"id":`+strconv.FormatInt(tsid.ToNumber(), 10)+`}`, string(data))
+	})
+}
+
+func Test_UnmarshalJSON(t *testing.T) {
+
+	t.Run("given string or number should decode same tsid", func(t *testing.T) {
+		expected := Fast()
+		inputs := []string{
+			`"` + expected.ToString() + `"`,
+			`"` + expected.ToLowerCase() + `"`,
+			strconv.FormatInt(expected.ToNumber(), 10),
+		}
+
+		for _, input := range inputs {
+			var tsid Tsid
+			err := json.Unmarshal([]byte(input), &tsid)
+			assert.Nil(t, err)
+			assert.Equal(t, expected.ToNumber(), tsid.ToNumber())
+
+			var numeric NumericTsid
+			err = json.Unmarshal([]byte(input), &numeric)
+			assert.Nil(t, err)
+			assert.Equal(t, expected.ToNumber(), numeric.ToNumber())
+		}
+	})
+
+	t.Run("given null should leave tsid unchanged", func(t *testing.T) {
+		expected := Fast()
+		tsid := expected
+
+		err := json.Unmarshal([]byte("null"), &tsid)
+		assert.Nil(t, err)
+		assert.Equal(t, expected.ToNumber(), tsid.ToNumber())
+	})
+
+	t.Run("given invalid value should return error", func(t *testing.T) {
+		var tsid Tsid
+
+		err := json.Unmarshal([]byte(`"0123456789AB#"`), &tsid)
+		assert.True(t, errors.Is(err, ErrInvalidCharacter))
+
+		assert.NotNil(t, json.Unmarshal([]byte(`1.5`), &tsid))
+		assert.NotNil(t, json.Unmarshal([]byte(`true`), &tsid))
+	})
+}
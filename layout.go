@@ -0,0 +1,152 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"fmt"
+	"time"
+)
+
+// Layout describes how the bits of a tsid are divided between the
+// time, node and counter components. From the most significant bit the
+// components are time, node and counter, unless CounterBeforeNode is set.
+type Layout struct {
+	TimeBits          int32         // bits used by the time component
+	TimeUnit          time.Duration // resolution of the time component, multiple of a millisecond
+	NodeBits          int32         // bits used by the node id
+	CounterBits       int32         // bits used by the counter
+	CounterBeforeNode bool          // places the counter above the node id, as done by sonyflake
+}
+
+// DefaultLayout is the layout of a tsid: 42 bits of milliseconds followed
+// by 22 random bits. The random bits are split between node and counter
+// using the node bits of the factory
+var DefaultLayout = Layout{
+	TimeBits:    42,
+	TimeUnit:    time.Millisecond,
+	NodeBits:    0,
+	CounterBits: RANDOM_BITS,
+}
+
+// Validate checks if the layout can be used to generate tsids. The widths
+// must sum to 63 or 64 bits, and node and counter together must fit in 31 bits.
+// Errors wrap ErrInvalidLayout
+func (l Layout) Validate() error {
+	if l.TimeUnit < time.Millisecond || l.TimeUnit%time.Millisecond != 0 {
+		return fmt.Errorf("%w: time unit must be a positive multiple of a millisecond: %s", ErrInvalidLayout, l.TimeUnit)
+	}
+
+	if l.TimeBits < 1 {
+		return fmt.Errorf("%w: time bits must be positive: %d", ErrInvalidLayout, l.TimeBits)
+	}
+
+	if l.NodeBits < 0 {
+		return fmt.Errorf("%w: node bits must not be negative: %d", ErrInvalidLayout, l.NodeBits)
+	}
+
+	if l.CounterBits < 1 {
+		return fmt.Errorf("%w: counter bits must be positive: %d", ErrInvalidLayout, l.CounterBits)
+	}
+
+	if l.RandomBits() > 31 {
+		return fmt.Errorf("%w: node and counter bits out of range [1, 31]: %d", ErrInvalidLayout, l.RandomBits())
+	}
+
+	total := l.TimeBits + l.RandomBits()
+	if total != 63 && total != 64 {
+		return fmt.Errorf("%w: layout must use 63 or 64 bits: %d", ErrInvalidLayout, total)
+	}
+	return nil
+}
+
+// RandomBits returns the bits used by node and counter together
+func (l Layout) RandomBits() int32 {
+	return l.NodeBits + l.CounterBits
+}
+
+// GetTime returns the time component of the tsid in time units since epoch
+func (l Layout) GetTime(t Tsid) int64 {
+	return int64(uint64(t.number) >> l.RandomBits())
+}
+
+// GetNode returns the node id of the tsid
+func (l Layout) GetNode(t Tsid) int32 {
+	return int32((uint64(t.number) >> l.nodeShift()) & uint64(mask(l.NodeBits)))
+}
+
+// GetCounter returns the counter of the tsid
+func (l Layout) GetCounter(t Tsid) int32 {
+	return int32((uint64(t.number) >> l.counterShift()) & uint64(mask(l.CounterBits)))
+}
+
+// GetRandom returns random component (node + counter) of the tsid
+func (l Layout) GetRandom(t Tsid) int64 {
+	return int64(uint64(t.number) & uint64(mask(l.RandomBits())))
+}
+
+// GetUnixMillis returns time of creation in millis since 1970-01-01
+// using the given epoch
+func (l Layout) GetUnixMillis(t Tsid, epoch int64) int64 {
+	return l.GetTime(t)*l.unitMillis() + epoch
+}
+
+// Decode splits the tsid into time (in time units since epoch), node
+// and counter components
+func (l Layout) Decode(t Tsid) (int64, int32, int32) {
+	return l.GetTime(t), l.GetNode(t), l.GetCounter(t)
+}
+
+// compose builds a tsid from time (in time units since epoch), node
+// and counter components
+func (l Layout) compose(time int64, node int32, counter int32) Tsid {
+	number := time << l.RandomBits()
+	number |= int64(node&mask(l.NodeBits)) << l.nodeShift()
+	number |= int64(counter&mask(l.CounterBits)) << l.counterShift()
+
+	return NewTsid(number)
+}
+
+// nodeShift returns the position of the node id
+func (l Layout) nodeShift() int32 {
+	if l.CounterBeforeNode {
+		return 0
+	}
+	return l.CounterBits
+}
+
+// counterShift returns the position of the counter
+func (l Layout) counterShift() int32 {
+	if l.CounterBeforeNode {
+		return l.NodeBits
+	}
+	return 0
+}
+
+// maxTime returns the largest time component which fits in the time bits
+func (l Layout) maxTime() int64 {
+	return int64(uint64(1)<<l.TimeBits - 1)
+}
+
+// unitMillis returns the time unit in milliseconds
+func (l Layout) unitMillis() int64 {
+	return l.TimeUnit.Milliseconds()
+}
+
+// mask returns an int32 with the given number of lower bits set
+func mask(bits int32) int32 {
+	return int32(uint32(1)<<bits - 1)
+}
@@ -0,0 +1,147 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Layout(t *testing.T) {
+
+	t.Run("given invalid layout should return error", func(t *testing.T) {
+		layouts := []Layout{
+			{TimeBits: 42, TimeUnit: 0, NodeBits: 10, CounterBits: 12},
+			{TimeBits: 42, TimeUnit: time.Microsecond, NodeBits: 10, CounterBits: 12},
+			{TimeBits: 42, TimeUnit: 1500 * time.Microsecond, NodeBits: 10, CounterBits: 12},
+			{TimeBits: 42, TimeUnit: time.Millisecond, NodeBits: 10, CounterBits: 10},
+			{TimeBits: 42, TimeUnit: time.Millisecond, NodeBits: -1, CounterBits: 23},
+			{TimeBits: 42, TimeUnit: time.Millisecond, NodeBits: 22, CounterBits: 0},
+			{TimeBits: 31, TimeUnit: time.Millisecond, NodeBits: 16, CounterBits: 16},
+		}
+
+		for _, layout := range layouts {
+			assert.True(t, errors.Is(layout.Validate(), ErrInvalidLayout), "layout: %+v", layout)
+
+			tsidFactory, err := TsidFactoryBuilder().
+				WithLayout(layout).
+				NewInstance()
+			assert.Nil(t, tsidFactory)
+			assert.True(t, errors.Is(err, ErrInvalidLayout), "layout: %+v", layout)
+			assert.Equal(t, layout.Validate(), errors.Unwrap(err))
+		}
+	})
+
+	t.Run("given valid layout should not return error", func(t *testing.T) {
+		layouts := []Layout{
+			DefaultLayout,
+			{TimeBits: 41, TimeUnit: time.Millisecond, NodeBits: 12, CounterBits: 10},
+			{TimeBits: 39, TimeUnit: 10 * time.Millisecond, NodeBits: 16, CounterBits: 8},
+			{TimeBits: 33, TimeUnit: time.Second, NodeBits: 0, CounterBits: 31},
+		}
+
+		for _, layout := range layouts {
+			assert.Nil(t, layout.Validate(), "layout: %+v", layout)
+		}
+	})
+
+	t.Run("given layout factory should generate tsid using the layout", func(t *testing.T) {
+		layout := Layout{TimeBits: 41, TimeUnit: 10 * time.Millisecond, NodeBits: 12, CounterBits: 10}
+		millis := TSID_EPOCH + 123_456_789_120
+
+		intRandom := NewIntRandomWithSupplierFunc(func() (int32, error) {
+			return 5, nil
+		})
+
+		tsidFactory, err := TsidFactoryBuilder().
+			WithLayout(layout).
+			WithNode(4000).
+			WithClock(time.UnixMilli(millis)).
+			WithRandom(intRandom).
+			NewInstance()
+		assert.Nil(t, err)
+		assert.Equal(t, layout, tsidFactory.GetLayout())
+
+		for i := 0; i < 3; i++ {
+			tsid, err := tsidFactory.Generate()
+			assert.Nil(t, err)
+
+			elapsed, node, counter := layout.Decode(tsid)
+			assert.Equal(t, int64(12_345_678_912), elapsed)
+			assert.Equal(t, int32(4000), node)
+			assert.Equal(t, int32(6+i), counter)
+
+			assert.Equal(t, millis, tsid.GetUnixMillisWithLayout(layout, TSID_EPOCH))
+			assert.Equal(t, int64(4000<<10|(6+i)), tsid.GetRandomWithLayout(layout))
+		}
+	})
+
+	t.Run("given layout node out of range should return error", func(t *testing.T) {
+		layout := Layout{TimeBits: 41, TimeUnit: time.Millisecond, NodeBits: 4, CounterBits: 18}
+
+		tsidFactory, err := TsidFactoryBuilder().
+			WithLayout(layout).
+			WithNode(16).
+			NewInstance()
+		assert.Nil(t, tsidFactory)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("default layout should match tsid components", func(t *testing.T) {
+		tsid := Fast()
+
+		assert.Equal(t, tsid.GetRandom(), DefaultLayout.GetRandom(tsid))
+		assert.Equal(t, tsid.GetUnixMillis(), DefaultLayout.GetUnixMillis(tsid, TSID_EPOCH))
+	})
+
+	t.Run("given time beyond time bits should return error", func(t *testing.T) {
+		layout := Layout{TimeBits: 40, TimeUnit: time.Millisecond, NodeBits: 0, CounterBits: 23}
+		epoch := int64(1000)
+		last := time.UnixMilli(epoch + int64(1)<<40 - 1)
+
+		for _, lockFree := range []bool{false, true} {
+			newFactory := func(clock Clock) *TsidFactory {
+				tsidFactory, err := TsidFactoryBuilder().
+					WithLayout(layout).
+					WithCustomEpoch(epoch).
+					WithClock(clock).
+					WithLockFree(lockFree).
+					NewInstance()
+				assert.Nil(t, err)
+				return tsidFactory
+			}
+
+			tsid, err := newFactory(last).Generate()
+			assert.Nil(t, err)
+			assert.Equal(t, last.UnixMilli(), layout.GetUnixMillis(tsid, epoch))
+
+			tsid, err = newFactory(last.Add(time.Millisecond)).Generate()
+			assert.Equal(t, Nil, tsid)
+			assert.True(t, errors.Is(err, ErrTimeOutOfRange))
+
+			// counter overflow borrows beyond the last time unit
+			_, err = newFactory(last).GenerateN(1 << 24)
+			assert.True(t, errors.Is(err, ErrTimeOutOfRange))
+
+			_, err = newFactory(time.UnixMilli(epoch - 1)).Generate()
+			assert.True(t, errors.Is(err, ErrTimeBeforeEpoch))
+		}
+	})
+}
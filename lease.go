@@ -0,0 +1,138 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+// NodeLeaser claims a free node id among the processes of a host. Each node
+// slot within 2^nodeBits has a lock file in the directory, and the first
+// slot whose file can be locked is held until Close is called or the
+// process exits. The locks are advisory, so all the processes must share
+// the same directory
+//
+// NodeLeaser is a NodeResolver, so it can be passed to the builder:
+//
+//	leaser := tsid.NewNodeLeaser("/var/run/tsid")
+//	defer leaser.Close()
+//
+//	tsidFactory, err := tsid.TsidFactoryBuilder().
+//		WithNodeBits(10).
+//		WithNodeResolvers(leaser).
+//		NewInstance()
+type NodeLeaser struct {
+	mu   sync.Mutex
+	dir  string
+	file *os.File
+	node int32
+}
+
+func NewNodeLeaser(dir string) *NodeLeaser {
+	return &NodeLeaser{
+		dir: dir,
+	}
+}
+
+// ResolveNode leases the first free node slot. Once leased, the same node
+// is returned until Close is called
+func (l *NodeLeaser) ResolveNode(nodeBits int32) (int32, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	max := mask(nodeBits)
+	if l.file != nil {
+		if l.node > max {
+			return 0, fmt.Errorf("%w [0, %d]: leased node %d", ErrNodeOutOfRange, max, l.node)
+		}
+		return l.node, nil
+	}
+
+	if err := os.MkdirAll(l.dir, 0o755); err != nil {
+		return 0, fmt.Errorf("failed to create node lease directory: %w", err)
+	}
+
+	for node := int32(0); node <= max; node++ {
+		file, err := l.tryLease(node)
+		if err != nil {
+			return 0, err
+		}
+		if file != nil {
+			l.file = file
+			l.node = node
+			return node, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: all %d node slots in %s are leased", ErrNodeUnavailable, max+1, l.dir)
+}
+
+// tryLease locks the file of the node slot. It returns a nil file when
+// the slot is held by another process
+func (l *NodeLeaser) tryLease(node int32) (*os.File, error) {
+	path := filepath.Join(l.dir, fmt.Sprintf("node-%d.lock", node))
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open node lease file: %w", err)
+	}
+
+	locked, err := tryLockFile(file)
+	if err != nil || !locked {
+		file.Close()
+		return nil, err
+	}
+
+	// the pid is informational only, the lock is what matters
+	if err := file.Truncate(0); err == nil {
+		file.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	}
+	return file, nil
+}
+
+// Node returns the leased node id, and false if no node is leased
+func (l *NodeLeaser) Node() (int32, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.node, l.file != nil
+}
+
+// Close releases the leased node, so that another process can claim it.
+// The lock file is kept, as removing it would race with other processes
+// locking it
+func (l *NodeLeaser) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+
+	file := l.file
+	l.file = nil
+	l.node = 0
+
+	err := unlockFile(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
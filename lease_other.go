@@ -0,0 +1,34 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"errors"
+	"os"
+)
+
+var errLockNotSupported = errors.New("node lease file locks are not supported on this platform")
+
+func tryLockFile(file *os.File) (bool, error) {
+	return false, errLockNotSupported
+}
+
+func unlockFile(file *os.File) error {
+	return errLockNotSupported
+}
@@ -0,0 +1,108 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NodeLeaser(t *testing.T) {
+
+	t.Run("should lease distinct nodes", func(t *testing.T) {
+		dir := t.TempDir()
+
+		first := NewNodeLeaser(dir)
+		defer first.Close()
+		second := NewNodeLeaser(dir)
+		defer second.Close()
+
+		node, err := first.ResolveNode(NODE_BITS_1024)
+		assert.Nil(t, err)
+		assert.Equal(t, int32(0), node)
+
+		node, err = second.ResolveNode(NODE_BITS_1024)
+		assert.Nil(t, err)
+		assert.Equal(t, int32(1), node)
+
+		// the lease is held until closed
+		node, err = first.ResolveNode(NODE_BITS_1024)
+		assert.Nil(t, err)
+		assert.Equal(t, int32(0), node)
+	})
+
+	t.Run("given closed leaser should release node", func(t *testing.T) {
+		dir := t.TempDir()
+
+		first := NewNodeLeaser(dir)
+		_, err := first.ResolveNode(NODE_BITS_1024)
+		assert.Nil(t, err)
+		assert.Nil(t, first.Close())
+
+		_, leased := first.Node()
+		assert.False(t, leased)
+
+		second := NewNodeLeaser(dir)
+		defer second.Close()
+
+		node, err := second.ResolveNode(NODE_BITS_1024)
+		assert.Nil(t, err)
+		assert.Equal(t, int32(0), node)
+	})
+
+	t.Run("given all nodes leased should return error", func(t *testing.T) {
+		dir := t.TempDir()
+
+		for i := 0; i < 2; i++ {
+			leaser := NewNodeLeaser(dir)
+			defer leaser.Close()
+
+			_, err := leaser.ResolveNode(1)
+			assert.Nil(t, err)
+		}
+
+		leaser := NewNodeLeaser(dir)
+		_, err := leaser.ResolveNode(1)
+		assert.True(t, errors.Is(err, ErrNodeUnavailable))
+	})
+
+	t.Run("should plug into builder", func(t *testing.T) {
+		dir := t.TempDir()
+
+		held := NewNodeLeaser(dir)
+		defer held.Close()
+		_, err := held.ResolveNode(NODE_BITS_1024)
+		assert.Nil(t, err)
+
+		leaser := NewNodeLeaser(dir)
+		defer leaser.Close()
+
+		tsidFactory, err := TsidFactoryBuilder().
+			WithNodeBits(NODE_BITS_1024).
+			WithNodeResolvers(leaser).
+			NewInstance()
+		assert.Nil(t, err)
+
+		tsid, err := tsidFactory.Generate()
+		assert.Nil(t, err)
+		assert.Equal(t, int32(1), tsidFactory.Decode(tsid).Node)
+	})
+}
@@ -0,0 +1,208 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
+)
+
+const (
+	ENV_NODE       = "TSID_NODE"
+	ENV_NODE_COUNT = "TSID_NODE_COUNT"
+
+	ENV_KUBERNETES_SERVICE_HOST = "KUBERNETES_SERVICE_HOST"
+)
+
+// NodeResolver resolves the node id of a factory, e.g. from the environment
+// of the process. It returns ErrNodeNotResolved when it does not apply, so
+// that the next resolver can be tried
+type NodeResolver interface {
+	ResolveNode(nodeBits int32) (int32, error)
+}
+
+// NodeResolverFunc adapts a function to NodeResolver
+type NodeResolverFunc func(nodeBits int32) (int32, error)
+
+func (f NodeResolverFunc) ResolveNode(nodeBits int32) (int32, error) {
+	return f(nodeBits)
+}
+
+// DefaultNodeResolvers returns the built-in resolvers in order of
+// precedence: environment variables, StatefulSet pod ordinal, hash of
+// the network interface and hash of the hostname
+func DefaultNodeResolvers() []NodeResolver {
+	return []NodeResolver{
+		NewEnvNodeResolver(),
+		NewStatefulSetNodeResolver(),
+		NewNetworkNodeResolver(),
+		NewHostnameNodeResolver(),
+	}
+}
+
+// envNodeResolver reads the node id from TSID_NODE. If TSID_NODE_COUNT is
+// set, the node id must be lower than it and it must fit in the node bits
+type envNodeResolver struct {
+	getenv func(key string) string
+}
+
+func NewEnvNodeResolver() *envNodeResolver {
+	return &envNodeResolver{
+		getenv: os.Getenv,
+	}
+}
+
+func (r *envNodeResolver) ResolveNode(nodeBits int32) (int32, error) {
+	value := r.getenv(ENV_NODE)
+	if value == "" {
+		return 0, fmt.Errorf("%w: %s is not set", ErrNodeNotResolved, ENV_NODE)
+	}
+
+	node, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", ENV_NODE, err)
+	}
+
+	max := int64(mask(nodeBits))
+	if count := r.getenv(ENV_NODE_COUNT); count != "" {
+		nodeCount, err := strconv.ParseInt(count, 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s: %w", ENV_NODE_COUNT, err)
+		}
+
+		if nodeCount < 1 || nodeCount > max+1 {
+			return 0, fmt.Errorf("%w: %s=%d does not fit in %d node bits",
+				ErrNodeOutOfRange, ENV_NODE_COUNT, nodeCount, nodeBits)
+		}
+		max = nodeCount - 1
+	}
+
+	if node < 0 || node > max {
+		return 0, fmt.Errorf("%w [0, %d]: %s=%d", ErrNodeOutOfRange, max, ENV_NODE, node)
+	}
+	return int32(node), nil
+}
+
+// hostnameNodeResolver hashes the hostname into the node bits
+type hostnameNodeResolver struct {
+	hostname func() (string, error)
+}
+
+func NewHostnameNodeResolver() *hostnameNodeResolver {
+	return &hostnameNodeResolver{
+		hostname: os.Hostname,
+	}
+}
+
+func (r *hostnameNodeResolver) ResolveNode(nodeBits int32) (int32, error) {
+	hostname, err := r.hostname()
+	if err != nil || hostname == "" {
+		return 0, fmt.Errorf("%w: hostname not available", ErrNodeNotResolved)
+	}
+	return hashNode([]byte(hostname), nodeBits), nil
+}
+
+// statefulSetNodeResolver reads the pod ordinal from a hostname like
+// "web-2", as assigned to the pods of a Kubernetes StatefulSet. It only
+// applies inside a Kubernetes pod, where KUBERNETES_SERVICE_HOST is set,
+// so that hostnames like "ip-10-0-1-23" are not mistaken for pods
+type statefulSetNodeResolver struct {
+	getenv   func(key string) string
+	hostname func() (string, error)
+}
+
+var statefulSetHostname = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?-(0|[1-9][0-9]*)$`)
+
+func NewStatefulSetNodeResolver() *statefulSetNodeResolver {
+	return &statefulSetNodeResolver{
+		getenv:   os.Getenv,
+		hostname: os.Hostname,
+	}
+}
+
+func (r *statefulSetNodeResolver) ResolveNode(nodeBits int32) (int32, error) {
+	if r.getenv(ENV_KUBERNETES_SERVICE_HOST) == "" {
+		return 0, fmt.Errorf("%w: not running in a kubernetes pod", ErrNodeNotResolved)
+	}
+
+	hostname, err := r.hostname()
+	if err != nil {
+		return 0, fmt.Errorf("%w: hostname not available", ErrNodeNotResolved)
+	}
+
+	match := statefulSetHostname.FindStringSubmatch(hostname)
+	if match == nil {
+		return 0, fmt.Errorf("%w: hostname %q has no pod ordinal", ErrNodeNotResolved, hostname)
+	}
+
+	// an ordinal which does not fit is an error rather than falling
+	// through, since a hashed node could collide with another pod
+	max := int64(mask(nodeBits))
+	ordinal, err := strconv.ParseInt(match[2], 10, 32)
+	if err != nil || ordinal > max {
+		return 0, fmt.Errorf("%w [0, %d]: pod ordinal %s of hostname %q", ErrNodeOutOfRange, max, match[2], hostname)
+	}
+	return int32(ordinal), nil
+}
+
+// networkNodeResolver hashes the hardware address, or else the first IP
+// address, of the first network interface which is up and not loopback
+type networkNodeResolver struct {
+	interfaces func() ([]net.Interface, error)
+}
+
+func NewNetworkNodeResolver() *networkNodeResolver {
+	return &networkNodeResolver{
+		interfaces: net.Interfaces,
+	}
+}
+
+func (r *networkNodeResolver) ResolveNode(nodeBits int32) (int32, error) {
+	interfaces, err := r.interfaces()
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrNodeNotResolved, err)
+	}
+
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+
+		if len(iface.HardwareAddr) > 0 {
+			return hashNode(iface.HardwareAddr, nodeBits), nil
+		}
+
+		addrs, err := iface.Addrs()
+		if err != nil || len(addrs) == 0 {
+			continue
+		}
+		return hashNode([]byte(addrs[0].String()), nodeBits), nil
+	}
+	return 0, fmt.Errorf("%w: no network interface found", ErrNodeNotResolved)
+}
+
+// hashNode hashes the data into the node bits
+func hashNode(data []byte, nodeBits int32) int32 {
+	hash := fnv.New32a()
+	hash.Write(data)
+
+	return int32(hash.Sum32() & uint32(mask(nodeBits)))
+}
@@ -0,0 +1,231 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_EnvNodeResolver(t *testing.T) {
+
+	t.Run("given environment variables should resolve node", func(t *testing.T) {
+		t.Setenv(ENV_NODE, "7")
+		t.Setenv(ENV_NODE_COUNT, "8")
+
+		node, err := NewEnvNodeResolver().ResolveNode(NODE_BITS_1024)
+		assert.Nil(t, err)
+		assert.Equal(t, int32(7), node)
+	})
+
+	t.Run("given no environment variable should not resolve node", func(t *testing.T) {
+		t.Setenv(ENV_NODE, "")
+
+		_, err := NewEnvNodeResolver().ResolveNode(NODE_BITS_1024)
+		assert.True(t, errors.Is(err, ErrNodeNotResolved))
+	})
+
+	t.Run("given node out of range should return error", func(t *testing.T) {
+		cases := []struct{ node, count string }{
+			{"1024", ""},
+			{"-1", ""},
+			{"8", "8"},
+			{"1", "2048"},
+		}
+
+		for _, c := range cases {
+			t.Setenv(ENV_NODE, c.node)
+			t.Setenv(ENV_NODE_COUNT, c.count)
+
+			_, err := NewEnvNodeResolver().ResolveNode(NODE_BITS_1024)
+			assert.True(t, errors.Is(err, ErrNodeOutOfRange), "node: %s, count: %s", c.node, c.count)
+		}
+	})
+
+	t.Run("given invalid node should return error", func(t *testing.T) {
+		t.Setenv(ENV_NODE, "one")
+
+		_, err := NewEnvNodeResolver().ResolveNode(NODE_BITS_1024)
+		assert.NotNil(t, err)
+		assert.False(t, errors.Is(err, ErrNodeNotResolved))
+	})
+}
+
+func Test_HostnameNodeResolvers(t *testing.T) {
+
+	hostname := func(name string) func() (string, error) {
+		return func() (string, error) {
+			return name, nil
+		}
+	}
+
+	t.Run("given hostname should hash it into node bits", func(t *testing.T) {
+		resolver := &hostnameNodeResolver{hostname: hostname("worker.example.com")}
+
+		node, err := resolver.ResolveNode(4)
+		assert.Nil(t, err)
+		assert.Equal(t, hashNode([]byte("worker.example.com"), 4), node)
+		assert.LessOrEqual(t, node, int32(15))
+	})
+
+	kubernetes := func(key string) string {
+		if key == ENV_KUBERNETES_SERVICE_HOST {
+			return "10.96.0.1"
+		}
+		return ""
+	}
+
+	t.Run("given statefulset hostname should resolve pod ordinal", func(t *testing.T) {
+		resolver := &statefulSetNodeResolver{getenv: kubernetes, hostname: hostname("web-12")}
+
+		node, err := resolver.ResolveNode(NODE_BITS_1024)
+		assert.Nil(t, err)
+		assert.Equal(t, int32(12), node)
+
+	})
+
+	t.Run("given pod ordinal out of range should return error", func(t *testing.T) {
+		for _, name := range []string{"web-1500", "worker-20231017", "web-99999999999"} {
+			resolver := &statefulSetNodeResolver{getenv: kubernetes, hostname: hostname(name)}
+
+			_, err := resolver.ResolveNode(NODE_BITS_1024)
+			assert.True(t, errors.Is(err, ErrNodeOutOfRange), name)
+		}
+
+		// does not fall through to the next resolver
+		tsidFactory, err := TsidFactoryBuilder().
+			WithNodeBits(NODE_BITS_1024).
+			WithNodeResolvers(
+				&statefulSetNodeResolver{getenv: kubernetes, hostname: hostname("web-1500")},
+				&hostnameNodeResolver{hostname: hostname("web-1500")}).
+			NewInstance()
+		assert.Nil(t, tsidFactory)
+		assert.True(t, errors.Is(err, ErrNodeOutOfRange))
+	})
+
+	t.Run("given hostname without ordinal should not resolve node", func(t *testing.T) {
+		for _, name := range []string{"web", "web-01"} {
+			resolver := &statefulSetNodeResolver{getenv: kubernetes, hostname: hostname(name)}
+
+			_, err := resolver.ResolveNode(NODE_BITS_1024)
+			assert.True(t, errors.Is(err, ErrNodeNotResolved), name)
+		}
+	})
+
+	t.Run("given hostname outside kubernetes should not resolve node", func(t *testing.T) {
+		resolver := &statefulSetNodeResolver{getenv: func(string) string { return "" }, hostname: hostname("ip-10-0-1-23")}
+
+		_, err := resolver.ResolveNode(NODE_BITS_1024)
+		assert.True(t, errors.Is(err, ErrNodeNotResolved))
+
+		// falls through to the next resolver
+		tsidFactory, err := TsidFactoryBuilder().
+			WithNodeBits(NODE_BITS_1024).
+			WithNodeResolvers(resolver, &hostnameNodeResolver{hostname: hostname("ip-10-0-1-23")}).
+			NewInstance()
+		assert.Nil(t, err)
+
+		tsid, err := tsidFactory.Generate()
+		assert.Nil(t, err)
+		assert.Equal(t, hashNode([]byte("ip-10-0-1-23"), NODE_BITS_1024), tsidFactory.Decode(tsid).Node)
+	})
+}
+
+func Test_NetworkNodeResolver(t *testing.T) {
+
+	t.Run("given network interface should hash hardware address", func(t *testing.T) {
+		mac := net.HardwareAddr{0x02, 0x42, 0xac, 0x11, 0x00, 0x02}
+		resolver := &networkNodeResolver{interfaces: func() ([]net.Interface, error) {
+			return []net.Interface{
+				{Name: "lo", Flags: net.FlagUp | net.FlagLoopback},
+				{Name: "eth0", Flags: net.FlagUp, HardwareAddr: mac},
+			}, nil
+		}}
+
+		node, err := resolver.ResolveNode(NODE_BITS_1024)
+		assert.Nil(t, err)
+		assert.Equal(t, hashNode(mac, NODE_BITS_1024), node)
+	})
+
+	t.Run("given no network interface should not resolve node", func(t *testing.T) {
+		resolver := &networkNodeResolver{interfaces: func() ([]net.Interface, error) {
+			return []net.Interface{{Name: "lo", Flags: net.FlagUp | net.FlagLoopback}}, nil
+		}}
+
+		_, err := resolver.ResolveNode(NODE_BITS_1024)
+		assert.True(t, errors.Is(err, ErrNodeNotResolved))
+	})
+}
+
+func Test_WithNodeResolvers(t *testing.T) {
+
+	notResolved := NodeResolverFunc(func(nodeBits int32) (int32, error) {
+		return 0, ErrNodeNotResolved
+	})
+
+	fixed := func(node int32) NodeResolver {
+		return NodeResolverFunc(func(nodeBits int32) (int32, error) {
+			return node, nil
+		})
+	}
+
+	t.Run("should use first resolver which applies", func(t *testing.T) {
+		tsidFactory, err := TsidFactoryBuilder().
+			WithNodeBits(NODE_BITS_1024).
+			WithNodeResolvers(notResolved, fixed(10), fixed(20)).
+			NewInstance()
+		assert.Nil(t, err)
+
+		tsid, err := tsidFactory.Generate()
+		assert.Nil(t, err)
+		assert.Equal(t, int32(10), tsidFactory.Decode(tsid).Node)
+	})
+
+	t.Run("given node should not use resolvers", func(t *testing.T) {
+		tsidFactory, err := TsidFactoryBuilder().
+			WithNodeBits(NODE_BITS_1024).
+			WithNode(5).
+			WithNodeResolvers(fixed(10)).
+			NewInstance()
+		assert.Nil(t, err)
+
+		tsid, err := tsidFactory.Generate()
+		assert.Nil(t, err)
+		assert.Equal(t, int32(5), tsidFactory.Decode(tsid).Node)
+	})
+
+	t.Run("given resolved node out of range should return error", func(t *testing.T) {
+		tsidFactory, err := TsidFactoryBuilder().
+			WithNodeBits(NODE_BITS_1024).
+			WithNodeResolvers(fixed(1024)).
+			NewInstance()
+		assert.Nil(t, tsidFactory)
+		assert.True(t, errors.Is(err, ErrNodeOutOfRange))
+	})
+
+	t.Run("given no resolver applies should return error", func(t *testing.T) {
+		tsidFactory, err := TsidFactoryBuilder().
+			WithNodeBits(NODE_BITS_1024).
+			WithNodeResolvers(notResolved).
+			NewInstance()
+		assert.Nil(t, tsidFactory)
+		assert.True(t, errors.Is(err, ErrNodeNotResolved))
+	})
+}
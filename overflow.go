@@ -0,0 +1,58 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"context"
+	"time"
+)
+
+// CounterOverflowPolicy decides what the factory does when the counter
+// is exhausted within a time unit
+type CounterOverflowPolicy uint8
+
+const (
+	// CounterOverflowBorrow carries the overflow into the time component,
+	// borrowing the next time unit ahead of the clock. This is the default
+	CounterOverflowBorrow CounterOverflowPolicy = iota
+
+	// CounterOverflowWait waits for the clock to reach the next time unit,
+	// so that tsids are never ahead of the clock. The wait can be bounded
+	// using GenerateContext
+	CounterOverflowWait
+)
+
+// sleep waits for the given duration or until the context is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// durationMillis converts millis to duration, waiting at least a millisecond
+func durationMillis(millis int64) time.Duration {
+	if millis < 1 {
+		millis = 1
+	}
+	return time.Duration(millis) * time.Millisecond
+}
@@ -0,0 +1,124 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CounterOverflowPolicy(t *testing.T) {
+
+	intRandom := NewIntRandomWithSupplierFunc(func() (int32, error) {
+		return 0, nil
+	})
+
+	t.Run("given borrow policy should count overflows", func(t *testing.T) {
+		for _, lockFree := range []bool{false, true} {
+
+			// counter has 2 bits, 4 tsids can be generated per millisecond
+			tsidFactory, err := TsidFactoryBuilder().
+				WithNodeBits(20).
+				WithClock(time.Now()).
+				WithRandom(intRandom).
+				WithLockFree(lockFree).
+				NewInstance()
+			assert.Nil(t, err)
+
+			for i := 0; i < 8; i++ {
+				_, err := tsidFactory.Generate()
+				assert.Nil(t, err)
+			}
+			assert.Equal(t, uint64(2), tsidFactory.CounterOverflows())
+
+			_, err = tsidFactory.GenerateN(8)
+			assert.Nil(t, err)
+			assert.Equal(t, uint64(3), tsidFactory.CounterOverflows())
+		}
+	})
+
+	t.Run("given wait policy tsids should not be ahead of clock", func(t *testing.T) {
+		for _, lockFree := range []bool{false, true} {
+			clock := NewSystemClock()
+
+			tsidFactory, err := TsidFactoryBuilder().
+				WithNodeBits(20).
+				WithClock(clock).
+				WithRandom(intRandom).
+				WithLockFree(lockFree).
+				WithCounterOverflowPolicy(CounterOverflowWait).
+				NewInstance()
+			assert.Nil(t, err)
+
+			last := Nil
+			for i := 0; i < 20; i++ {
+				tsid, err := tsidFactory.GenerateContext(context.Background())
+				assert.Nil(t, err)
+
+				assert.LessOrEqual(t, tsid.GetUnixMillis(), clock.UnixMilli())
+				assert.True(t, last.Before(tsid))
+				last = tsid
+			}
+			assert.Greater(t, tsidFactory.CounterOverflows(), uint64(0))
+
+			tsids, err := tsidFactory.GenerateN(20)
+			assert.Nil(t, err)
+			assert.Len(t, tsids, 20)
+
+			for _, tsid := range tsids {
+				assert.LessOrEqual(t, tsid.GetUnixMillis(), clock.UnixMilli())
+				assert.True(t, last.Before(tsid))
+				last = tsid
+			}
+		}
+	})
+
+	t.Run("given wait policy should honor context deadline", func(t *testing.T) {
+		for _, lockFree := range []bool{false, true} {
+
+			// clock never moves, the counter can not be renewed
+			tsidFactory, err := TsidFactoryBuilder().
+				WithNodeBits(20).
+				WithClock(time.Now()).
+				WithRandom(intRandom).
+				WithLockFree(lockFree).
+				WithCounterOverflowPolicy(CounterOverflowWait).
+				NewInstance()
+			assert.Nil(t, err)
+
+			for i := 0; i < 3; i++ {
+				_, err := tsidFactory.Generate()
+				assert.Nil(t, err)
+			}
+			assert.Equal(t, uint64(0), tsidFactory.CounterOverflows())
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			tsid, err := tsidFactory.GenerateContext(ctx)
+			cancel()
+
+			assert.Equal(t, Nil, tsid)
+			assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+			// counted once, however many times it waited
+			assert.Equal(t, uint64(1), tsidFactory.CounterOverflows())
+		}
+	})
+}
@@ -0,0 +1,251 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+const (
+	PREFIX_SEPARATOR  = '_'
+	PREFIX_MAX_LENGTH = 63
+)
+
+// PrefixedTsid is a tsid with a type prefix, like "usr_01226N0640J7K". The
+// prefix has up to 63 lower case ASCII letters, and is separated from the
+// canonical string of the tsid by an underscore. An empty prefix renders
+// the tsid alone
+type PrefixedTsid struct {
+	Prefix string
+	Tsid   Tsid
+}
+
+// NewPrefixedTsid returns the tsid with the prefix. It returns
+// ErrInvalidPrefix if the prefix does not follow the prefix rules
+func NewPrefixedTsid(prefix string, tsid Tsid) (PrefixedTsid, error) {
+	if err := ValidatePrefix(prefix); err != nil {
+		return PrefixedTsid{}, err
+	}
+	return PrefixedTsid{Prefix: prefix, Tsid: tsid}, nil
+}
+
+// ValidatePrefix checks that the prefix has up to 63 lower case ASCII
+// letters
+func ValidatePrefix(prefix string) error {
+	if len(prefix) > PREFIX_MAX_LENGTH {
+		return fmt.Errorf("%w: longer than %d characters: %q", ErrInvalidPrefix, PREFIX_MAX_LENGTH, prefix)
+	}
+
+	for i := 0; i < len(prefix); i++ {
+		if prefix[i] < 'a' || prefix[i] > 'z' {
+			return fmt.Errorf("%w: %q at position %d", ErrInvalidPrefix, prefix[i], i)
+		}
+	}
+	return nil
+}
+
+// ParsePrefixedTsid parses a prefixed tsid with any valid prefix. A string
+// without separator is parsed as a tsid with an empty prefix
+func ParsePrefixedTsid(str string) (PrefixedTsid, error) {
+	prefix, tsidStr := "", str
+	if i := strings.LastIndexByte(str, PREFIX_SEPARATOR); i >= 0 {
+		prefix, tsidStr = str[:i], str[i+1:]
+
+		if prefix == "" {
+			return PrefixedTsid{}, fmt.Errorf("%w: empty prefix before separator: %q", ErrInvalidPrefix, str)
+		}
+	}
+
+	if err := ValidatePrefix(prefix); err != nil {
+		return PrefixedTsid{}, err
+	}
+
+	tsid, err := FromString(tsidStr)
+	if err != nil {
+		return PrefixedTsid{}, err
+	}
+	return PrefixedTsid{Prefix: prefix, Tsid: tsid}, nil
+}
+
+// ParsePrefixedTsidWithPrefix parses a prefixed tsid and returns
+// ErrPrefixMismatch if it does not have the expected prefix, e.g. to
+// reject an order id where a user id is expected
+func ParsePrefixedTsidWithPrefix(str string, prefix string) (PrefixedTsid, error) {
+	parsed, err := ParsePrefixedTsid(str)
+	if err != nil {
+		return PrefixedTsid{}, err
+	}
+
+	if parsed.Prefix != prefix {
+		return PrefixedTsid{}, fmt.Errorf("%w: expected %q, got %q", ErrPrefixMismatch, prefix, parsed.Prefix)
+	}
+	return parsed, nil
+}
+
+// String returns the prefix, the separator and the canonical string of
+// the tsid
+func (p PrefixedTsid) String() string {
+	if p.Prefix == "" {
+		return p.Tsid.ToString()
+	}
+	return p.Prefix + string(PREFIX_SEPARATOR) + p.Tsid.ToString()
+}
+
+// IsZero checks if the tsid is the zero value Nil
+func (p PrefixedTsid) IsZero() bool {
+	return p.Tsid.IsZero()
+}
+
+// MarshalText encodes the prefixed tsid as string
+func (p PrefixedTsid) MarshalText() ([]byte, error) {
+	if err := ValidatePrefix(p.Prefix); err != nil {
+		return nil, err
+	}
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText decodes the prefixed tsid from string. If the prefix is
+// already set, e.g. to the zero value of the field, the decoded prefix
+// must match it
+func (p *PrefixedTsid) UnmarshalText(text []byte) error {
+	var parsed PrefixedTsid
+	var err error
+	if p.Prefix != "" {
+		parsed, err = ParsePrefixedTsidWithPrefix(string(text), p.Prefix)
+	} else {
+		parsed, err = ParsePrefixedTsid(string(text))
+	}
+
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
+}
+
+// MarshalJSON encodes the prefixed tsid as a JSON string
+func (p PrefixedTsid) MarshalJSON() ([]byte, error) {
+	text, err := p.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON decodes the prefixed tsid from a JSON string like
+// UnmarshalText. A JSON null leaves the prefixed tsid unchanged
+func (p *PrefixedTsid) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, jsonNull) {
+		return nil
+	}
+
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return fmt.Errorf("invalid prefixed tsid json value %s: %w", data, err)
+	}
+	return p.UnmarshalText([]byte(str))
+}
+
+// prefixRegistry maps prefixes to Go types and back
+var prefixRegistry = struct {
+	sync.RWMutex
+	types    map[string]reflect.Type
+	prefixes map[reflect.Type]string
+}{
+	types:    make(map[string]reflect.Type),
+	prefixes: make(map[reflect.Type]string),
+}
+
+// RegisterPrefix registers the prefix of the ids of the type T, e.g.
+// RegisterPrefix[User]("usr"). A prefix can only be registered for one
+// type, and a type can only have one prefix
+func RegisterPrefix[T any](prefix string) error {
+	if err := ValidatePrefix(prefix); err != nil {
+		return err
+	}
+	if prefix == "" {
+		return fmt.Errorf("%w: empty prefix", ErrInvalidPrefix)
+	}
+
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+
+	prefixRegistry.Lock()
+	defer prefixRegistry.Unlock()
+
+	if registered, ok := prefixRegistry.types[prefix]; ok && registered != typ {
+		return fmt.Errorf("%w: %q is registered for %s", ErrPrefixRegistered, prefix, registered)
+	}
+	if registered, ok := prefixRegistry.prefixes[typ]; ok && registered != prefix {
+		return fmt.Errorf("%w: %s is registered with %q", ErrPrefixRegistered, typ, registered)
+	}
+
+	prefixRegistry.types[prefix] = typ
+	prefixRegistry.prefixes[typ] = prefix
+	return nil
+}
+
+// PrefixFor returns the prefix registered for the type T
+func PrefixFor[T any]() (string, bool) {
+	prefixRegistry.RLock()
+	defer prefixRegistry.RUnlock()
+
+	prefix, ok := prefixRegistry.prefixes[reflect.TypeOf((*T)(nil)).Elem()]
+	return prefix, ok
+}
+
+// TypeForPrefix returns the type registered for the prefix
+func TypeForPrefix(prefix string) (reflect.Type, bool) {
+	prefixRegistry.RLock()
+	defer prefixRegistry.RUnlock()
+
+	typ, ok := prefixRegistry.types[prefix]
+	return typ, ok
+}
+
+// NewPrefixedTsidFor returns the tsid with the prefix registered for the
+// type T. It returns ErrPrefixNotRegistered if there is none
+func NewPrefixedTsidFor[T any](tsid Tsid) (PrefixedTsid, error) {
+	prefix, err := registeredPrefix[T]()
+	if err != nil {
+		return PrefixedTsid{}, err
+	}
+	return PrefixedTsid{Prefix: prefix, Tsid: tsid}, nil
+}
+
+// ParsePrefixedTsidFor parses a prefixed tsid with the prefix registered
+// for the type T
+func ParsePrefixedTsidFor[T any](str string) (PrefixedTsid, error) {
+	prefix, err := registeredPrefix[T]()
+	if err != nil {
+		return PrefixedTsid{}, err
+	}
+	return ParsePrefixedTsidWithPrefix(str, prefix)
+}
+
+func registeredPrefix[T any]() (string, error) {
+	prefix, ok := PrefixFor[T]()
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrPrefixNotRegistered, reflect.TypeOf((*T)(nil)).Elem())
+	}
+	return prefix, nil
+}
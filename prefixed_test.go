@@ -0,0 +1,146 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PrefixedTsid(t *testing.T) {
+	tsid, _ := FromString("01226N0640J7K")
+
+	t.Run("should format and parse prefixed tsid", func(t *testing.T) {
+		prefixed, err := NewPrefixedTsid("usr", tsid)
+		assert.Nil(t, err)
+		assert.Equal(t, "usr_01226N0640J7K", prefixed.String())
+
+		parsed, err := ParsePrefixedTsid("usr_01226N0640J7K")
+		assert.Nil(t, err)
+		assert.Equal(t, prefixed, parsed)
+
+		// without prefix
+		parsed, err = ParsePrefixedTsid("01226N0640J7K")
+		assert.Nil(t, err)
+		assert.Equal(t, PrefixedTsid{Tsid: tsid}, parsed)
+		assert.Equal(t, "01226N0640J7K", parsed.String())
+	})
+
+	t.Run("given invalid prefix should return error", func(t *testing.T) {
+		invalid := []string{"Usr", "us1", "us-r", "us_r", "üsr", strings.Repeat("a", 64)}
+
+		for _, prefix := range invalid {
+			_, err := NewPrefixedTsid(prefix, tsid)
+			assert.True(t, errors.Is(err, ErrInvalidPrefix), prefix)
+		}
+
+		for _, str := range []string{"_01226N0640J7K", "Usr_01226N0640J7K", "us_r_01226N0640J7K"} {
+			_, err := ParsePrefixedTsid(str)
+			assert.True(t, errors.Is(err, ErrInvalidPrefix), str)
+		}
+
+		_, err := ParsePrefixedTsid("usr_01226N0640J7")
+		assert.True(t, errors.Is(err, ErrInvalidLength))
+	})
+
+	t.Run("given other prefix should return error", func(t *testing.T) {
+		_, err := ParsePrefixedTsidWithPrefix("ord_01226N0640J7K", "usr")
+		assert.True(t, errors.Is(err, ErrPrefixMismatch))
+
+		parsed, err := ParsePrefixedTsidWithPrefix("usr_01226N0640J7K", "usr")
+		assert.Nil(t, err)
+		assert.Equal(t, tsid, parsed.Tsid)
+	})
+
+	t.Run("should marshal json", func(t *testing.T) {
+		type User struct {
+			Id PrefixedTsid `json:"id"`
+		}
+
+		data, err := json.Marshal(User{Id: PrefixedTsid{Prefix: "usr", Tsid: tsid}})
+		assert.Nil(t, err)
+		assert.Equal(t, `{"id":"usr_01226N0640J7K"}`, string(data))
+
+		var user User
+		assert.Nil(t, json.Unmarshal(data, &user))
+		assert.Equal(t, "usr", user.Id.Prefix)
+		assert.Equal(t, tsid, user.Id.Tsid)
+
+		// expected prefix set before decoding
+		user = User{Id: PrefixedTsid{Prefix: "usr"}}
+		err = json.Unmarshal([]byte(`{"id":"ord_01226N0640J7K"}`), &user)
+		assert.True(t, errors.Is(err, ErrPrefixMismatch))
+
+		_, err = json.Marshal(User{Id: PrefixedTsid{Prefix: "USR", Tsid: tsid}})
+		assert.True(t, errors.Is(err, ErrInvalidPrefix))
+	})
+
+	t.Run("should marshal text", func(t *testing.T) {
+		prefixed := PrefixedTsid{Prefix: "usr", Tsid: tsid}
+
+		text, err := prefixed.MarshalText()
+		assert.Nil(t, err)
+
+		var decoded PrefixedTsid
+		assert.Nil(t, decoded.UnmarshalText(text))
+		assert.Equal(t, prefixed, decoded)
+	})
+}
+
+func Test_RegisterPrefix(t *testing.T) {
+	type prefixedUser struct{}
+	type prefixedOrder struct{}
+	type prefixedOther struct{}
+
+	assert.Nil(t, RegisterPrefix[prefixedUser]("testusr"))
+	assert.Nil(t, RegisterPrefix[prefixedUser]("testusr"))
+	assert.Nil(t, RegisterPrefix[prefixedOrder]("testord"))
+
+	err := RegisterPrefix[prefixedOther]("testusr")
+	assert.True(t, errors.Is(err, ErrPrefixRegistered))
+	err = RegisterPrefix[prefixedUser]("testother")
+	assert.True(t, errors.Is(err, ErrPrefixRegistered))
+	err = RegisterPrefix[prefixedOther]("")
+	assert.True(t, errors.Is(err, ErrInvalidPrefix))
+
+	prefix, ok := PrefixFor[prefixedUser]()
+	assert.True(t, ok)
+	assert.Equal(t, "testusr", prefix)
+
+	typ, ok := TypeForPrefix("testord")
+	assert.True(t, ok)
+	assert.Equal(t, reflect.TypeOf(prefixedOrder{}), typ)
+
+	tsid := Fast()
+	prefixed, err := NewPrefixedTsidFor[prefixedUser](tsid)
+	assert.Nil(t, err)
+
+	parsed, err := ParsePrefixedTsidFor[prefixedUser](prefixed.String())
+	assert.Nil(t, err)
+	assert.Equal(t, tsid, parsed.Tsid)
+
+	_, err = ParsePrefixedTsidFor[prefixedOrder](prefixed.String())
+	assert.True(t, errors.Is(err, ErrPrefixMismatch))
+
+	_, err = NewPrefixedTsidFor[prefixedOther](tsid)
+	assert.True(t, errors.Is(err, ErrPrefixNotRegistered))
+}
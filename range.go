@@ -0,0 +1,112 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"fmt"
+	"time"
+)
+
+// MinForTime returns the smallest tsid generated at the instant, e.g. to
+// query a range of tsids in a database
+func MinForTime(t time.Time) (Tsid, error) {
+	return DefaultLayout.MinForTime(t, TSID_EPOCH)
+}
+
+// MinForTimeWithCustomEpoch returns the smallest tsid generated at the
+// instant using the custom epoch
+func MinForTimeWithCustomEpoch(t time.Time, epoch int64) (Tsid, error) {
+	return DefaultLayout.MinForTime(t, epoch)
+}
+
+// MaxForTime returns the largest tsid generated at the instant
+func MaxForTime(t time.Time) (Tsid, error) {
+	return DefaultLayout.MaxForTime(t, TSID_EPOCH)
+}
+
+// MaxForTimeWithCustomEpoch returns the largest tsid generated at the
+// instant using the custom epoch
+func MaxForTimeWithCustomEpoch(t time.Time, epoch int64) (Tsid, error) {
+	return DefaultLayout.MaxForTime(t, epoch)
+}
+
+// RangeForInterval returns the smallest tsid generated at from and the
+// largest one generated at to. Both are inclusive, so the tsids of the
+// interval can be queried using "id BETWEEN min AND max"
+func RangeForInterval(from, to time.Time) (Tsid, Tsid, error) {
+	return DefaultLayout.RangeForInterval(from, to, TSID_EPOCH)
+}
+
+// RangeForIntervalWithCustomEpoch returns the range of tsids generated in
+// the interval using the custom epoch
+func RangeForIntervalWithCustomEpoch(from, to time.Time, epoch int64) (Tsid, Tsid, error) {
+	return DefaultLayout.RangeForInterval(from, to, epoch)
+}
+
+// MinForTime returns the smallest tsid of the layout generated at the
+// instant. Node and counter are zero
+func (l Layout) MinForTime(t time.Time, epoch int64) (Tsid, error) {
+	units, err := l.timeSinceEpoch(t, epoch)
+	if err != nil {
+		return Nil, err
+	}
+	return NewTsid(units << l.RandomBits()), nil
+}
+
+// MaxForTime returns the largest tsid of the layout generated at the
+// instant. All the node and counter bits are set
+func (l Layout) MaxForTime(t time.Time, epoch int64) (Tsid, error) {
+	units, err := l.timeSinceEpoch(t, epoch)
+	if err != nil {
+		return Nil, err
+	}
+	return NewTsid(units<<l.RandomBits() | int64(mask(l.RandomBits()))), nil
+}
+
+// RangeForInterval returns the smallest tsid of the layout generated at
+// from and the largest one generated at to
+func (l Layout) RangeForInterval(from, to time.Time, epoch int64) (Tsid, Tsid, error) {
+	if to.Before(from) {
+		return Nil, Nil, fmt.Errorf("invalid interval: %s is before %s", to, from)
+	}
+
+	min, err := l.MinForTime(from, epoch)
+	if err != nil {
+		return Nil, Nil, err
+	}
+
+	max, err := l.MaxForTime(to, epoch)
+	if err != nil {
+		return Nil, Nil, err
+	}
+	return min, max, nil
+}
+
+// timeSinceEpoch returns the time component of the instant, in time units
+// since the epoch
+func (l Layout) timeSinceEpoch(t time.Time, epoch int64) (int64, error) {
+	millis := t.UnixMilli()
+	if millis < epoch {
+		return 0, fmt.Errorf("%w: %s", ErrTimeBeforeEpoch, t.UTC().Format(time.RFC3339Nano))
+	}
+
+	units := (millis - epoch) / l.unitMillis()
+	if units > l.maxTime() {
+		return 0, fmt.Errorf("%w of %d bits: %s", ErrTimeOutOfRange, l.TimeBits, t.UTC().Format(time.RFC3339Nano))
+	}
+	return units, nil
+}
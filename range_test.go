@@ -0,0 +1,123 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MinMaxForTime(t *testing.T) {
+
+	t.Run("should bound tsids generated at instant", func(t *testing.T) {
+		instant := time.UnixMilli(TSID_EPOCH + 123456789)
+
+		// a random counter near the end would borrow the next millisecond
+		intRandom := NewIntRandomWithSupplierFunc(func() (int32, error) {
+			return 0, nil
+		})
+
+		tsidFactory, err := TsidFactoryBuilder().
+			WithClock(instant).
+			WithRandom(intRandom).
+			WithNodeBits(NODE_BITS_1024).
+			NewInstance()
+		assert.Nil(t, err)
+
+		min, err := MinForTime(instant)
+		assert.Nil(t, err)
+		max, err := MaxForTime(instant)
+		assert.Nil(t, err)
+
+		assert.Equal(t, instant.UnixMilli(), min.GetUnixMillis())
+		assert.Equal(t, instant.UnixMilli(), max.GetUnixMillis())
+		assert.Equal(t, int64(0), min.GetRandom())
+		assert.Equal(t, int64(RANDOM_MASK), max.GetRandom())
+
+		for i := 0; i < 100; i++ {
+			tsid, err := tsidFactory.Generate()
+			assert.Nil(t, err)
+			assert.False(t, tsid.Before(min))
+			assert.False(t, tsid.After(max))
+		}
+
+		next, err := MinForTime(instant.Add(time.Millisecond))
+		assert.Nil(t, err)
+		assert.True(t, next.After(max))
+	})
+
+	t.Run("given custom epoch should bound tsids", func(t *testing.T) {
+		instant := time.UnixMilli(DISCORD_EPOCH + 1000)
+
+		min, err := MinForTimeWithCustomEpoch(instant, DISCORD_EPOCH)
+		assert.Nil(t, err)
+		assert.Equal(t, instant.UnixMilli(), min.GetUnixMillisWithCustomEpoch(DISCORD_EPOCH))
+
+		max, err := MaxForTimeWithCustomEpoch(instant, DISCORD_EPOCH)
+		assert.Nil(t, err)
+		assert.Equal(t, instant.UnixMilli(), max.GetUnixMillisWithCustomEpoch(DISCORD_EPOCH))
+	})
+
+	t.Run("given time before epoch should return error", func(t *testing.T) {
+		_, err := MinForTime(time.UnixMilli(TSID_EPOCH - 1))
+		assert.True(t, errors.Is(err, ErrTimeBeforeEpoch))
+
+		_, err = MaxForTimeWithCustomEpoch(time.UnixMilli(TSID_EPOCH), TSID_EPOCH+1)
+		assert.True(t, errors.Is(err, ErrTimeBeforeEpoch))
+	})
+
+	t.Run("given time beyond 42 bits should return error", func(t *testing.T) {
+		last := time.UnixMilli(TSID_EPOCH + 1<<42 - 1)
+
+		max, err := MaxForTime(last)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(-1), max.ToNumber())
+
+		_, err = MinForTime(last.Add(time.Millisecond))
+		assert.True(t, errors.Is(err, ErrTimeOutOfRange))
+	})
+
+	t.Run("given layout should truncate to time unit", func(t *testing.T) {
+		instant := time.UnixMilli(SONYFLAKE_EPOCH + 12345)
+
+		min, err := SonyflakeLayout.MinForTime(instant, SONYFLAKE_EPOCH)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(SONYFLAKE_EPOCH+12340), min.GetUnixMillisWithLayout(SonyflakeLayout, SONYFLAKE_EPOCH))
+	})
+}
+
+func Test_RangeForInterval(t *testing.T) {
+	from := time.UnixMilli(TSID_EPOCH + 1000)
+	to := from.Add(time.Hour)
+
+	min, max, err := RangeForInterval(from, to)
+	assert.Nil(t, err)
+	assert.Equal(t, from.UnixMilli(), min.GetUnixMillis())
+	assert.Equal(t, to.UnixMilli(), max.GetUnixMillis())
+	assert.True(t, min.Before(max))
+
+	min, max, err = RangeForIntervalWithCustomEpoch(from, from, TSID_EPOCH)
+	assert.Nil(t, err)
+	assert.Equal(t, from.UnixMilli(), min.GetUnixMillis())
+	assert.Equal(t, from.UnixMilli(), max.GetUnixMillis())
+
+	_, _, err = RangeForInterval(to, from)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"fmt"
+	"time"
+)
+
+// ClockRegressionPolicy decides what the factory does when the clock
+// moves backwards, e.g. due to NTP step or VM migration
+type ClockRegressionPolicy uint8
+
+const (
+	// ClockRegressionBorrow keeps incrementing the counter against the
+	// last time until the clock catches up. This is the default
+	ClockRegressionBorrow ClockRegressionPolicy = iota
+
+	// ClockRegressionWait blocks until the clock catches up. Regressions
+	// longer than the limit return ErrClockMovedBackwards
+	ClockRegressionWait
+
+	// ClockRegressionFail returns ErrClockMovedBackwards
+	ClockRegressionFail
+
+	// ClockRegressionBorrowThenFail borrows like ClockRegressionBorrow
+	// for regressions up to the limit and returns ErrClockMovedBackwards
+	// for longer ones
+	ClockRegressionBorrowThenFail
+)
+
+// ClockRegression describes the clock moving backwards
+type ClockRegression struct {
+	Previous time.Time     // last clock reading
+	Current  time.Time     // clock reading which is before the previous one
+	Drift    time.Duration // how far the clock moved backwards
+}
+
+// checkRegression applies the regression policy to a clock which moved
+// backwards by drift. It returns how long to wait for the clock to catch
+// up, or an error if the tsid should not be generated
+func (factory *TsidFactory) checkRegression(drift time.Duration) (time.Duration, error) {
+	switch factory.regressionPolicy {
+	case ClockRegressionWait:
+		if drift > factory.regressionLimit {
+			return 0, fmt.Errorf("%w: by %s, more than max wait %s",
+				ErrClockMovedBackwards, drift, factory.regressionLimit)
+		}
+		return drift, nil
+
+	case ClockRegressionFail:
+		return 0, fmt.Errorf("%w: by %s", ErrClockMovedBackwards, drift)
+
+	case ClockRegressionBorrowThenFail:
+		if drift > factory.regressionLimit {
+			return 0, fmt.Errorf("%w: by %s, more than threshold %s",
+				ErrClockMovedBackwards, drift, factory.regressionLimit)
+		}
+	}
+	return 0, nil
+}
+
+// newClockRegression describes the clock moving from previous to current,
+// both in time units since the epoch
+func (factory *TsidFactory) newClockRegression(previous int64, current int64) ClockRegression {
+	unit := factory.layout.unitMillis()
+
+	return ClockRegression{
+		Previous: time.UnixMilli(previous*unit + factory.customEpoch).UTC(),
+		Current:  time.UnixMilli(current*unit + factory.customEpoch).UTC(),
+		Drift:    time.Duration(previous-current) * factory.layout.TimeUnit,
+	}
+}
@@ -0,0 +1,156 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ClockRegressionPolicy(t *testing.T) {
+
+	epoch := time.Now().UnixMilli()
+
+	intRandom := NewIntRandomWithSupplierFunc(func() (int32, error) {
+		return 0, nil
+	})
+
+	t.Run("given borrow policy should call handler once per regression", func(t *testing.T) {
+		for _, lockFree := range []bool{false, true} {
+			clock := &MockClock{
+				millis: []int64{epoch, epoch + 5, epoch + 3, epoch + 4, epoch + 6, epoch + 2},
+			}
+
+			var regressions []ClockRegression
+			tsidFactory, err := TsidFactoryBuilder().
+				WithClock(clock).
+				WithRandom(intRandom).
+				WithLockFree(lockFree).
+				WithClockRegressionHandler(func(regression ClockRegression) {
+					regressions = append(regressions, regression)
+				}).
+				NewInstance()
+			assert.Nil(t, err)
+
+			var millis []int64
+			for i := 0; i < 5; i++ {
+				tsid, err := tsidFactory.Generate()
+				assert.Nil(t, err)
+				millis = append(millis, tsid.GetUnixMillis())
+			}
+
+			assert.Equal(t, []int64{epoch + 5, epoch + 5, epoch + 5, epoch + 6, epoch + 6}, millis)
+			assert.Equal(t, []ClockRegression{
+				{
+					Previous: time.UnixMilli(epoch + 5).UTC(),
+					Current:  time.UnixMilli(epoch + 3).UTC(),
+					Drift:    2 * time.Millisecond,
+				},
+				{
+					Previous: time.UnixMilli(epoch + 6).UTC(),
+					Current:  time.UnixMilli(epoch + 2).UTC(),
+					Drift:    4 * time.Millisecond,
+				},
+			}, regressions)
+		}
+	})
+
+	t.Run("given fail policy should return error", func(t *testing.T) {
+		for _, lockFree := range []bool{false, true} {
+			clock := &MockClock{
+				millis: []int64{epoch, epoch + 5, epoch + 3, epoch + 5},
+			}
+
+			tsidFactory, err := TsidFactoryBuilder().
+				WithClock(clock).
+				WithRandom(intRandom).
+				WithLockFree(lockFree).
+				WithClockRegressionPolicy(ClockRegressionFail, 0).
+				NewInstance()
+			assert.Nil(t, err)
+
+			_, err = tsidFactory.Generate()
+			assert.Nil(t, err)
+
+			tsid, err := tsidFactory.Generate()
+			assert.Equal(t, Nil, tsid)
+			assert.True(t, errors.Is(err, ErrClockMovedBackwards))
+
+			// clock caught up
+			_, err = tsidFactory.Generate()
+			assert.Nil(t, err)
+		}
+	})
+
+	t.Run("given wait policy should wait for clock to catch up", func(t *testing.T) {
+		for _, lockFree := range []bool{false, true} {
+			clock := &MockClock{
+				millis: []int64{epoch, epoch + 5, epoch + 3, epoch + 7, epoch - 100},
+			}
+
+			tsidFactory, err := TsidFactoryBuilder().
+				WithClock(clock).
+				WithRandom(intRandom).
+				WithLockFree(lockFree).
+				WithClockRegressionPolicy(ClockRegressionWait, 10*time.Millisecond).
+				NewInstance()
+			assert.Nil(t, err)
+
+			_, err = tsidFactory.Generate()
+			assert.Nil(t, err)
+
+			start := time.Now()
+			tsid, err := tsidFactory.Generate()
+			assert.Nil(t, err)
+			assert.Equal(t, epoch+7, tsid.GetUnixMillis())
+			assert.GreaterOrEqual(t, time.Since(start), 2*time.Millisecond)
+
+			// regression longer than max wait
+			_, err = tsidFactory.Generate()
+			assert.True(t, errors.Is(err, ErrClockMovedBackwards))
+		}
+	})
+
+	t.Run("given borrow then fail policy should fail beyond threshold", func(t *testing.T) {
+		for _, lockFree := range []bool{false, true} {
+			clock := &MockClock{
+				millis: []int64{epoch, epoch + 10, epoch + 5, epoch - 10},
+			}
+
+			tsidFactory, err := TsidFactoryBuilder().
+				WithClock(clock).
+				WithRandom(intRandom).
+				WithLockFree(lockFree).
+				WithClockRegressionPolicy(ClockRegressionBorrowThenFail, 5*time.Millisecond).
+				NewInstance()
+			assert.Nil(t, err)
+
+			_, err = tsidFactory.Generate()
+			assert.Nil(t, err)
+
+			tsid, err := tsidFactory.Generate()
+			assert.Nil(t, err)
+			assert.Equal(t, epoch+10, tsid.GetUnixMillis())
+
+			_, err = tsidFactory.Generate()
+			assert.True(t, errors.Is(err, ErrClockMovedBackwards))
+		}
+	})
+}
@@ -0,0 +1,158 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import "time"
+
+const (
+	SNOWFLAKE_EPOCH int64 = 1288834974657 // 2010-11-04T01:42:54.657Z
+	SONYFLAKE_EPOCH int64 = 1409529600000 // 2014-09-01T00:00:00.000Z
+	DISCORD_EPOCH   int64 = 1420070400000 // 2015-01-01T00:00:00.000Z
+)
+
+// SnowflakeLayout is the layout of Twitter snowflake ids: 41 bits of
+// milliseconds, 10 bits of worker id (5 bits datacenter + 5 bits worker)
+// and 12 bits of sequence
+var SnowflakeLayout = Layout{
+	TimeBits:    41,
+	TimeUnit:    time.Millisecond,
+	NodeBits:    10,
+	CounterBits: 12,
+}
+
+// SonyflakeLayout is the layout of Sonyflake ids: 39 bits of time in
+// units of 10 milliseconds, 8 bits of sequence and 16 bits of machine id
+var SonyflakeLayout = Layout{
+	TimeBits:          39,
+	TimeUnit:          10 * time.Millisecond,
+	NodeBits:          16,
+	CounterBits:       8,
+	CounterBeforeNode: true,
+}
+
+// DiscordLayout is the layout of Discord snowflake ids: 42 bits of
+// milliseconds, 5 bits of worker id, 5 bits of process id and 12 bits
+// of increment
+var DiscordLayout = Layout{
+	TimeBits:    42,
+	TimeUnit:    time.Millisecond,
+	NodeBits:    10,
+	CounterBits: 12,
+}
+
+// SnowflakeFactoryBuilder returns a builder preconfigured to generate
+// Twitter snowflake ids. Use SnowflakeNode to compute the node id
+func SnowflakeFactoryBuilder() *tsidFactoryBuilder {
+	return TsidFactoryBuilder().
+		WithLayout(SnowflakeLayout).
+		WithCustomEpoch(SNOWFLAKE_EPOCH).
+		WithRandom(newZeroRandom())
+}
+
+// SonyflakeFactoryBuilder returns a builder preconfigured to generate
+// Sonyflake ids. The node id is used as machine id
+func SonyflakeFactoryBuilder() *tsidFactoryBuilder {
+	return TsidFactoryBuilder().
+		WithLayout(SonyflakeLayout).
+		WithCustomEpoch(SONYFLAKE_EPOCH).
+		WithRandom(newZeroRandom())
+}
+
+// DiscordFactoryBuilder returns a builder preconfigured to generate
+// Discord snowflake ids. Use DiscordNode to compute the node id
+func DiscordFactoryBuilder() *tsidFactoryBuilder {
+	return TsidFactoryBuilder().
+		WithLayout(DiscordLayout).
+		WithCustomEpoch(DISCORD_EPOCH).
+		WithRandom(newZeroRandom())
+}
+
+// SnowflakeNode returns the node id made of the datacenter id and
+// worker id, both in range [0, 31]
+func SnowflakeNode(datacenter int32, worker int32) int32 {
+	return (datacenter&0x1f)<<5 | (worker & 0x1f)
+}
+
+// DiscordNode returns the node id made of the worker id and process id,
+// both in range [0, 31]
+func DiscordNode(worker int32, process int32) int32 {
+	return (worker&0x1f)<<5 | (process & 0x1f)
+}
+
+// Snowflake contains the components of a Twitter snowflake id
+type Snowflake struct {
+	Time       time.Time
+	Datacenter int32
+	Worker     int32
+	Sequence   int32
+}
+
+// Sonyflake contains the components of a Sonyflake id
+type Sonyflake struct {
+	Time      time.Time
+	MachineID int32
+	Sequence  int32
+}
+
+// DiscordSnowflake contains the components of a Discord snowflake id
+type DiscordSnowflake struct {
+	Time      time.Time
+	Worker    int32
+	Process   int32
+	Increment int32
+}
+
+// DecodeSnowflake splits the Twitter snowflake id into its components
+func DecodeSnowflake(t Tsid) Snowflake {
+	node := SnowflakeLayout.GetNode(t)
+
+	return Snowflake{
+		Time:       time.UnixMilli(SnowflakeLayout.GetUnixMillis(t, SNOWFLAKE_EPOCH)).UTC(),
+		Datacenter: node >> 5,
+		Worker:     node & 0x1f,
+		Sequence:   SnowflakeLayout.GetCounter(t),
+	}
+}
+
+// DecodeSonyflake splits the Sonyflake id into its components
+func DecodeSonyflake(t Tsid) Sonyflake {
+	return Sonyflake{
+		Time:      time.UnixMilli(SonyflakeLayout.GetUnixMillis(t, SONYFLAKE_EPOCH)).UTC(),
+		MachineID: SonyflakeLayout.GetNode(t),
+		Sequence:  SonyflakeLayout.GetCounter(t),
+	}
+}
+
+// DecodeDiscord splits the Discord snowflake id into its components
+func DecodeDiscord(t Tsid) DiscordSnowflake {
+	node := DiscordLayout.GetNode(t)
+
+	return DiscordSnowflake{
+		Time:      time.UnixMilli(DiscordLayout.GetUnixMillis(t, DISCORD_EPOCH)).UTC(),
+		Worker:    node >> 5,
+		Process:   node & 0x1f,
+		Increment: DiscordLayout.GetCounter(t),
+	}
+}
+
+// newZeroRandom returns random which always returns zero, so that the
+// sequence starts from zero in every time unit like snowflake does
+func newZeroRandom() Random {
+	return NewIntRandomWithSupplierFunc(func() (int32, error) {
+		return 0, nil
+	})
+}
@@ -0,0 +1,124 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Snowflake(t *testing.T) {
+
+	t.Run("should decode snowflake id", func(t *testing.T) {
+		millis := SNOWFLAKE_EPOCH + 1_000_000
+		id := FromNumber(1_000_000<<22 | 17<<17 | 9<<12 | 42)
+
+		snowflake := DecodeSnowflake(id)
+		assert.Equal(t, time.UnixMilli(millis).UTC(), snowflake.Time)
+		assert.Equal(t, int32(17), snowflake.Datacenter)
+		assert.Equal(t, int32(9), snowflake.Worker)
+		assert.Equal(t, int32(42), snowflake.Sequence)
+	})
+
+	t.Run("should generate snowflake id", func(t *testing.T) {
+		now := time.Now()
+
+		tsidFactory, err := SnowflakeFactoryBuilder().
+			WithNode(SnowflakeNode(3, 7)).
+			WithClock(now).
+			NewInstance()
+		assert.Nil(t, err)
+
+		for i := 0; i < 5; i++ {
+			id, err := tsidFactory.Generate()
+			assert.Nil(t, err)
+
+			snowflake := DecodeSnowflake(id)
+			assert.Equal(t, now.UnixMilli(), snowflake.Time.UnixMilli())
+			assert.Equal(t, int32(3), snowflake.Datacenter)
+			assert.Equal(t, int32(7), snowflake.Worker)
+			assert.Equal(t, int32(i+1), snowflake.Sequence)
+			assert.True(t, id.ToNumber() > 0)
+		}
+	})
+}
+
+func Test_Sonyflake(t *testing.T) {
+
+	t.Run("should decode sonyflake id", func(t *testing.T) {
+		id := FromNumber(1_000_000<<24 | 200<<16 | 65_000)
+
+		sonyflake := DecodeSonyflake(id)
+		assert.Equal(t, time.UnixMilli(SONYFLAKE_EPOCH+10_000_000).UTC(), sonyflake.Time)
+		assert.Equal(t, int32(65_000), sonyflake.MachineID)
+		assert.Equal(t, int32(200), sonyflake.Sequence)
+	})
+
+	t.Run("should generate sonyflake id", func(t *testing.T) {
+		millis := SONYFLAKE_EPOCH + 123_456_789
+
+		tsidFactory, err := SonyflakeFactoryBuilder().
+			WithNode(0xbeef).
+			WithClock(time.UnixMilli(millis)).
+			NewInstance()
+		assert.Nil(t, err)
+
+		for i := 0; i < 5; i++ {
+			id, err := tsidFactory.Generate()
+			assert.Nil(t, err)
+
+			sonyflake := DecodeSonyflake(id)
+			assert.Equal(t, millis-millis%10, sonyflake.Time.UnixMilli())
+			assert.Equal(t, int32(0xbeef), sonyflake.MachineID)
+			assert.Equal(t, int32(i+1), sonyflake.Sequence)
+		}
+	})
+}
+
+func Test_Discord(t *testing.T) {
+
+	t.Run("should decode discord id", func(t *testing.T) {
+		// example from the discord api reference
+		discord := DecodeDiscord(FromNumber(175928847299117063))
+
+		assert.Equal(t, int64(1462015105796), discord.Time.UnixMilli())
+		assert.Equal(t, int32(1), discord.Worker)
+		assert.Equal(t, int32(0), discord.Process)
+		assert.Equal(t, int32(7), discord.Increment)
+	})
+
+	t.Run("should generate discord id", func(t *testing.T) {
+		now := time.Now()
+
+		tsidFactory, err := DiscordFactoryBuilder().
+			WithNode(DiscordNode(1, 2)).
+			WithClock(now).
+			NewInstance()
+		assert.Nil(t, err)
+
+		id, err := tsidFactory.Generate()
+		assert.Nil(t, err)
+
+		discord := DecodeDiscord(id)
+		assert.Equal(t, now.UnixMilli(), discord.Time.UnixMilli())
+		assert.Equal(t, int32(1), discord.Worker)
+		assert.Equal(t, int32(2), discord.Process)
+		assert.Equal(t, int32(1), discord.Increment)
+	})
+}
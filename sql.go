@@ -0,0 +1,192 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ValueMode decides how a tsid is represented when it is written
+// to a database column
+type ValueMode uint8
+
+const (
+	// ValueNumber stores the tsid as int64, e.g. BIGINT columns
+	ValueNumber ValueMode = iota
+
+	// ValueBytes stores the tsid as 8 bytes in big-endian order,
+	// e.g. BINARY(8) columns
+	ValueBytes
+
+	// ValueString stores the tsid as canonical string, e.g. TEXT or
+	// CHAR(13) columns
+	ValueString
+)
+
+// value returns the driver value of the tsid for the mode
+func (mode ValueMode) value(t Tsid) (driver.Value, error) {
+	switch mode {
+	case ValueNumber:
+		return t.ToNumber(), nil
+	case ValueBytes:
+		return t.ToBytes(), nil
+	case ValueString:
+		return t.ToString(), nil
+	}
+	return nil, fmt.Errorf("invalid tsid value mode: %d", mode)
+}
+
+// Value implements driver.Valuer. The tsid is stored as int64, use
+// BytesTsid or StringTsid for other column types
+func (t Tsid) Value() (driver.Value, error) {
+	return ValueNumber.value(t)
+}
+
+// Scan implements sql.Scanner. It accepts int64, 8 bytes in big-endian
+// order, canonical string or a number formatted as string. A value made
+// of decimal digits is read as a number, as returned by some drivers for
+// integer columns, even if it is 8 bytes or 13 characters long. Use
+// BytesTsid or StringTsid to read those columns unambiguously
+func (t *Tsid) Scan(src any) error {
+	return t.scan(src, ValueNumber)
+}
+
+// scan converts the driver value of a column written using the mode
+func (t *Tsid) scan(src any, mode ValueMode) error {
+	var tsid Tsid
+	var err error
+
+	switch value := src.(type) {
+	case int64:
+		tsid = NewTsid(value)
+	case []byte:
+		if mode == ValueBytes || (len(value) == int(TSID_BYTES) && !isDecimal(string(value))) {
+			tsid, err = FromBytes(value)
+		} else {
+			tsid, err = scanString(string(value), mode)
+		}
+	case string:
+		tsid, err = scanString(value, mode)
+	case nil:
+		err = errors.New("cannot scan NULL into tsid, use NullTsid instead")
+	default:
+		err = fmt.Errorf("cannot scan %T into tsid", src)
+	}
+
+	if err != nil {
+		return err
+	}
+	*t = tsid
+	return nil
+}
+
+// scanString converts a number formatted as string, as returned by some
+// drivers for integer columns, or else a canonical string to tsid. Using
+// ValueString, the string is always canonical
+func scanString(str string, mode ValueMode) (Tsid, error) {
+	if mode != ValueString && isDecimal(str) {
+		number, err := strconv.ParseInt(str, 10, 64)
+		if err != nil {
+			return Nil, fmt.Errorf("cannot scan %q into tsid: %w", str, err)
+		}
+		return NewTsid(number), nil
+	}
+	return FromString(str)
+}
+
+// isDecimal reports whether the string is an integer made of decimal
+// digits, with an optional minus sign
+func isDecimal(str string) bool {
+	if len(str) > 0 && str[0] == '-' {
+		str = str[1:]
+	}
+	if len(str) == 0 {
+		return false
+	}
+
+	for i := 0; i < len(str); i++ {
+		if str[i] < '0' || str[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// BytesTsid wraps a tsid so that it is stored as 8 bytes in big-endian
+// order, e.g. in MySQL BINARY(8) columns
+type BytesTsid struct {
+	Tsid
+}
+
+// Value implements driver.Valuer
+func (b BytesTsid) Value() (driver.Value, error) {
+	return ValueBytes.value(b.Tsid)
+}
+
+// Scan implements sql.Scanner. Bytes are always read in big-endian order
+func (b *BytesTsid) Scan(src any) error {
+	return b.Tsid.scan(src, ValueBytes)
+}
+
+// StringTsid wraps a tsid so that it is stored as canonical string,
+// e.g. in SQLite TEXT columns
+type StringTsid struct {
+	Tsid
+}
+
+// Value implements driver.Valuer
+func (s StringTsid) Value() (driver.Value, error) {
+	return ValueString.value(s.Tsid)
+}
+
+// Scan implements sql.Scanner. Strings are always read as canonical string
+func (s *StringTsid) Scan(src any) error {
+	return s.Tsid.scan(src, ValueString)
+}
+
+// NullTsid represents a tsid that may be null. Mode decides how a valid
+// tsid is stored and scanned, default is ValueNumber
+type NullTsid struct {
+	Tsid  Tsid
+	Valid bool // Valid is true if Tsid is not NULL
+	Mode  ValueMode
+}
+
+// Scan implements sql.Scanner
+func (n *NullTsid) Scan(src any) error {
+	if src == nil {
+		n.Tsid, n.Valid = Tsid{}, false
+		return nil
+	}
+
+	if err := n.Tsid.scan(src, n.Mode); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// Value implements driver.Valuer
+func (n NullTsid) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Mode.value(n.Tsid)
+}
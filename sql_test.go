@@ -0,0 +1,259 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SQL(t *testing.T) {
+
+	db, err := sql.Open("tsid_fake", "")
+	assert.Nil(t, err)
+	defer db.Close()
+
+	t.Run("should store tsid as number by default", func(t *testing.T) {
+		expected := Fast()
+
+		_, err := db.Exec("INSERT number", expected)
+		assert.Nil(t, err)
+
+		var number int64
+		assert.Nil(t, db.QueryRow("SELECT number").Scan(&number))
+		assert.Equal(t, expected.ToNumber(), number)
+
+		var tsid Tsid
+		assert.Nil(t, db.QueryRow("SELECT number").Scan(&tsid))
+		assert.Equal(t, expected, tsid)
+	})
+
+	t.Run("given bytes tsid should store tsid as bytes", func(t *testing.T) {
+		expected := Fast()
+
+		_, err := db.Exec("INSERT bytes", BytesTsid{expected})
+		assert.Nil(t, err)
+
+		var bytes []byte
+		assert.Nil(t, db.QueryRow("SELECT bytes").Scan(&bytes))
+		assert.Equal(t, expected.ToBytes(), bytes)
+
+		var tsid BytesTsid
+		assert.Nil(t, db.QueryRow("SELECT bytes").Scan(&tsid))
+		assert.Equal(t, expected, tsid.Tsid)
+	})
+
+	t.Run("given string tsid should store tsid as canonical string", func(t *testing.T) {
+		expected := Fast()
+
+		_, err := db.Exec("INSERT string", StringTsid{expected})
+		assert.Nil(t, err)
+
+		var str string
+		assert.Nil(t, db.QueryRow("SELECT string").Scan(&str))
+		assert.Equal(t, expected.ToString(), str)
+
+		var tsid StringTsid
+		assert.Nil(t, db.QueryRow("SELECT string").Scan(&tsid))
+		assert.Equal(t, expected, tsid.Tsid)
+	})
+
+	t.Run("given null tsid should store null", func(t *testing.T) {
+		_, err := db.Exec("INSERT null", NullTsid{})
+		assert.Nil(t, err)
+
+		tsid := NullTsid{Valid: true}
+		assert.Nil(t, db.QueryRow("SELECT null").Scan(&tsid))
+		assert.False(t, tsid.Valid)
+
+		var notNull Tsid
+		assert.NotNil(t, db.QueryRow("SELECT null").Scan(&notNull))
+	})
+
+	t.Run("given valid null tsid should store tsid using mode", func(t *testing.T) {
+		expected := Fast()
+
+		for _, mode := range []ValueMode{ValueNumber, ValueBytes, ValueString} {
+			table := "nullable" + strconv.Itoa(int(mode))
+
+			_, err := db.Exec("INSERT "+table, NullTsid{Tsid: expected, Valid: true, Mode: mode})
+			assert.Nil(t, err)
+
+			var tsid NullTsid
+			assert.Nil(t, db.QueryRow("SELECT "+table).Scan(&tsid))
+			assert.True(t, tsid.Valid)
+			assert.Equal(t, expected, tsid.Tsid)
+		}
+	})
+}
+
+func Test_Scan(t *testing.T) {
+
+	t.Run("given number formatted as string should scan tsid", func(t *testing.T) {
+		expected := Fast()
+		number := strconv.FormatInt(expected.ToNumber(), 10)
+
+		var tsid Tsid
+		assert.Nil(t, tsid.Scan(number))
+		assert.Equal(t, expected, tsid)
+
+		assert.Nil(t, tsid.Scan([]byte(number)))
+		assert.Equal(t, expected, tsid)
+	})
+
+	t.Run("given digits of ambiguous length should scan number", func(t *testing.T) {
+		// 8 digits, as returned by the MySQL text protocol, are not raw
+		// bytes and 13 digits are not a canonical string
+		for _, number := range []int64{12345678, 1234567890123, -1234567} {
+			str := strconv.FormatInt(number, 10)
+
+			var tsid Tsid
+			assert.Nil(t, tsid.Scan([]byte(str)), str)
+			assert.Equal(t, NewTsid(number), tsid)
+
+			assert.Nil(t, tsid.Scan(str), str)
+			assert.Equal(t, NewTsid(number), tsid)
+		}
+
+		// raw bytes and canonical strings are still scanned
+		expected := NewTsid(0x0102030405060708)
+		var tsid Tsid
+		assert.Nil(t, tsid.Scan(expected.ToBytes()))
+		assert.Equal(t, expected, tsid)
+		assert.Nil(t, tsid.Scan(expected.ToString()))
+		assert.Equal(t, expected, tsid)
+	})
+
+	t.Run("given column mode should scan digits in that mode", func(t *testing.T) {
+		var b BytesTsid
+		assert.Nil(t, b.Scan([]byte("12345678")))
+		expected, err := FromBytes([]byte("12345678"))
+		assert.Nil(t, err)
+		assert.Equal(t, expected, b.Tsid)
+
+		var s StringTsid
+		assert.Nil(t, s.Scan("0123456789012"))
+		expected, err = FromString("0123456789012")
+		assert.Nil(t, err)
+		assert.Equal(t, expected, s.Tsid)
+
+		n := NullTsid{Mode: ValueBytes}
+		assert.Nil(t, n.Scan([]byte("12345678")))
+		assert.True(t, n.Valid)
+		assert.Equal(t, b.Tsid, n.Tsid)
+	})
+
+	t.Run("given unsupported value should return error", func(t *testing.T) {
+		var tsid Tsid
+		assert.NotNil(t, tsid.Scan(1.5))
+		assert.NotNil(t, tsid.Scan("invalid"))
+		assert.NotNil(t, tsid.Scan([]byte{1, 2, 3}))
+	})
+}
+
+// fakeDriver is an in-memory driver which supports two statements:
+// "INSERT <table>" replaces the only row of the table with the arguments
+// and "SELECT <table>" returns it
+type fakeDriver struct {
+	mu     sync.Mutex
+	tables map[string][]driver.Value
+}
+
+func init() {
+	sql.Register("tsid_fake", &fakeDriver{tables: map[string][]driver.Value{}})
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) {
+	return &fakeConn{driver: d}, nil
+}
+
+type fakeConn struct {
+	driver *fakeDriver
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{conn: c, query: strings.Fields(query)}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, driver.ErrSkip
+}
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query []string
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.conn.driver.mu.Lock()
+	defer s.conn.driver.mu.Unlock()
+
+	s.conn.driver.tables[s.query[1]] = args
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.conn.driver.mu.Lock()
+	defer s.conn.driver.mu.Unlock()
+
+	return &fakeRows{row: s.conn.driver.tables[s.query[1]]}, nil
+}
+
+type fakeRows struct {
+	row  []driver.Value
+	done bool
+}
+
+func (r *fakeRows) Columns() []string {
+	columns := make([]string, len(r.row))
+	for i := range columns {
+		columns[i] = "column" + strconv.Itoa(i)
+	}
+	return columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	copy(dest, r.row)
+	return nil
+}
@@ -0,0 +1,181 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StateStore persists the high-water mark of a factory, in unix millis, so
+// that a restarted factory does not generate tsids below the ones that
+// were already generated
+type StateStore interface {
+	// Load returns the saved high-water mark, or zero if there is none
+	Load() (int64, error)
+	Save(millis int64) error
+}
+
+// fileStateStore saves the high-water mark to a file. The mark is written
+// to a temporary file which is synced and renamed over the previous one,
+// so that the file never holds a partially written mark
+type fileStateStore struct {
+	path string
+}
+
+func NewFileStateStore(path string) *fileStateStore {
+	return &fileStateStore{
+		path: path,
+	}
+}
+
+func (s *fileStateStore) Load() (int64, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read tsid state: %w", err)
+	}
+
+	millis, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid tsid state in %s: %w", s.path, err)
+	}
+	return millis, nil
+}
+
+func (s *fileStateStore) Save(millis int64) error {
+	dir := filepath.Dir(s.path)
+
+	file, err := os.CreateTemp(dir, filepath.Base(s.path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to save tsid state: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.WriteString(strconv.FormatInt(millis, 10) + "\n")
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), s.path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save tsid state: %w", err)
+	}
+
+	// sync the directory so that the rename is durable. Not every
+	// platform supports it, so errors are ignored
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
+
+// loadState starts the factory after the saved high-water mark. If the
+// clock is behind the mark, it is handled like the clock moving backwards
+// according to the regression policy
+func (factory *TsidFactory) loadState(store StateStore, window time.Duration) error {
+	mark, err := store.Load()
+	if err != nil {
+		return err
+	}
+
+	factory.store = store
+	factory.storeWindow = window
+	factory.storeMark.Store(mark)
+
+	if mark < factory.customEpoch {
+		return nil
+	}
+
+	time := factory.elapsed(mark)
+	if time < factory.lastTime {
+		return nil
+	}
+
+	// the next tsid borrows the time unit after the mark, unless
+	// the clock moves past it first
+	factory.lastTime = time
+	factory.counter = factory.counterMask
+	factory.state.Store(time<<factory.counterBits | int64(factory.counterMask))
+	factory.lastClock.Store(time)
+	return nil
+}
+
+// persistState saves a new high-water mark, one window ahead, before
+// tsids up to the time are returned. The mark is saved at most once
+// per window while tsids are generated
+func (factory *TsidFactory) persistState(time int64) error {
+	millis := factory.endMillis(time)
+	if millis <= factory.storeMark.Load() {
+		return nil
+	}
+
+	factory.storeMu.Lock()
+	defer factory.storeMu.Unlock()
+
+	if millis <= factory.storeMark.Load() {
+		return nil
+	}
+
+	mark := millis + factory.storeWindow.Milliseconds()
+	if err := factory.store.Save(mark); err != nil {
+		return err
+	}
+	factory.storeMark.Store(mark)
+	return nil
+}
+
+// saveState saves the time of the last generated tsid as high-water
+// mark, so that a restarted factory does not need to skip the window
+func (factory *TsidFactory) saveState() error {
+	factory.storeMu.Lock()
+	defer factory.storeMu.Unlock()
+
+	var time int64
+	if factory.lockFree {
+		time = factory.state.Load() >> factory.counterBits
+	} else {
+		factory.mu.Lock()
+		time = factory.lastTime
+		factory.mu.Unlock()
+	}
+
+	millis := factory.endMillis(time)
+	if err := factory.store.Save(millis); err != nil {
+		return err
+	}
+	factory.storeMark.Store(millis)
+	return nil
+}
+
+// endMillis returns the last unix milli of the time unit
+func (factory *TsidFactory) endMillis(time int64) int64 {
+	unit := factory.layout.unitMillis()
+	return (time+1)*unit - 1 + factory.customEpoch
+}
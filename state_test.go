@@ -0,0 +1,189 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryStateStore keeps the high-water mark in memory
+type memoryStateStore struct {
+	mark  int64
+	saves int
+	err   error
+}
+
+func (s *memoryStateStore) Load() (int64, error) {
+	return s.mark, s.err
+}
+
+func (s *memoryStateStore) Save(millis int64) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.mark = millis
+	s.saves++
+	return nil
+}
+
+func Test_FileStateStore(t *testing.T) {
+
+	t.Run("should save and load high-water mark", func(t *testing.T) {
+		dir := t.TempDir()
+		store := NewFileStateStore(filepath.Join(dir, "tsid.state"))
+
+		mark, err := store.Load()
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), mark)
+
+		assert.Nil(t, store.Save(1700000000000))
+		assert.Nil(t, store.Save(1700000001000))
+
+		mark, err = store.Load()
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1700000001000), mark)
+
+		// temporary files are renamed or removed
+		entries, err := os.ReadDir(dir)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(entries))
+	})
+
+	t.Run("given invalid state should return error", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tsid.state")
+		assert.Nil(t, os.WriteFile(path, []byte("invalid"), 0o644))
+
+		_, err := NewFileStateStore(path).Load()
+		assert.NotNil(t, err)
+	})
+}
+
+func Test_WithStateStore(t *testing.T) {
+
+	for _, lockFree := range []bool{false, true} {
+
+		newFactory := func(clock Clock, store StateStore, policy ClockRegressionPolicy) (*TsidFactory, error) {
+			return TsidFactoryBuilder().
+				WithClock(clock).
+				WithLockFree(lockFree).
+				WithClockRegressionPolicy(policy, 0).
+				WithStateStore(store, time.Second).
+				NewInstance()
+		}
+
+		t.Run("should save mark ahead of generated tsids", func(t *testing.T) {
+			clock := newSettableClock()
+			store := &memoryStateStore{}
+
+			tsidFactory, err := newFactory(clock, store, ClockRegressionBorrow)
+			assert.Nil(t, err)
+
+			tsid, err := tsidFactory.Generate()
+			assert.Nil(t, err)
+			assert.Equal(t, tsid.GetUnixMillis()+1000, store.mark)
+
+			// saved once per window
+			clock.Advance(500 * time.Millisecond)
+			_, err = tsidFactory.Generate()
+			assert.Nil(t, err)
+			assert.Equal(t, 1, store.saves)
+
+			clock.Advance(600 * time.Millisecond)
+			tsid, err = tsidFactory.Generate()
+			assert.Nil(t, err)
+			assert.Equal(t, 2, store.saves)
+			assert.Equal(t, tsid.GetUnixMillis()+1000, store.mark)
+
+			// close saves the last generated tsid
+			assert.Nil(t, tsidFactory.Close())
+			assert.Equal(t, tsid.GetUnixMillis(), store.mark)
+		})
+
+		t.Run("given window below a millisecond should return error", func(t *testing.T) {
+			for _, window := range []time.Duration{-time.Second, 0, time.Microsecond} {
+				store := &memoryStateStore{}
+				tsidFactory, err := TsidFactoryBuilder().
+					WithLockFree(lockFree).
+					WithStateStore(store, window).
+					NewInstance()
+				assert.Nil(t, tsidFactory)
+				assert.NotNil(t, err)
+				assert.Equal(t, 0, store.saves)
+			}
+		})
+
+		t.Run("given restart should generate tsids after previous ones", func(t *testing.T) {
+			clock := newSettableClock()
+			store := &memoryStateStore{}
+
+			tsidFactory, err := newFactory(clock, store, ClockRegressionBorrow)
+			assert.Nil(t, err)
+			tsids, err := tsidFactory.GenerateN(1000)
+			assert.Nil(t, err)
+			last := tsids[len(tsids)-1]
+
+			// restarted within the same millisecond, and after the
+			// clock moved backwards
+			for _, rollback := range []time.Duration{0, time.Minute} {
+				clock.Advance(-rollback)
+
+				restarted, err := newFactory(clock, store, ClockRegressionBorrow)
+				assert.Nil(t, err)
+
+				tsid, err := restarted.Generate()
+				assert.Nil(t, err)
+				assert.True(t, tsid.After(last))
+				last = tsid
+			}
+		})
+
+		t.Run("given fail policy should refuse tsids below mark", func(t *testing.T) {
+			clock := newSettableClock()
+			store := &memoryStateStore{mark: clock.UnixMilli() + 1000}
+
+			tsidFactory, err := newFactory(clock, store, ClockRegressionFail)
+			assert.Nil(t, err)
+
+			_, err = tsidFactory.Generate()
+			assert.True(t, errors.Is(err, ErrClockMovedBackwards))
+
+			clock.Advance(time.Second)
+			_, err = tsidFactory.Generate()
+			assert.Nil(t, err)
+		})
+
+		t.Run("given store error should not return tsids", func(t *testing.T) {
+			store := &memoryStateStore{}
+
+			tsidFactory, err := newFactory(newSettableClock(), store, ClockRegressionBorrow)
+			assert.Nil(t, err)
+
+			store.err = errors.New("disk full")
+			_, err = tsidFactory.Generate()
+			assert.Equal(t, store.err, err)
+
+			_, err = newFactory(newSettableClock(), store, ClockRegressionBorrow)
+			assert.True(t, errors.Is(err, store.err))
+		})
+	}
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package tsid
 
 import (
+	"fmt"
+	"sort"
 	"sync/atomic"
 	"time"
 )
@@ -109,19 +111,24 @@ func init() {
 	ALPHABET_VALUES['O'] = 0x00
 }
 
+// Tsid is a comparable value type, it can be compared using == and used
+// as map key. The zero value is Nil
 type Tsid struct {
 	number int64
 }
 
-// NewTsid returns pointer to new tsid
-func NewTsid(number int64) *Tsid {
-	return &Tsid{
+// Nil is the zero value of tsid
+var Nil = Tsid{}
+
+// NewTsid returns new tsid
+func NewTsid(number int64) Tsid {
+	return Tsid{
 		number: number,
 	}
 }
 
-// Fast returns a pointer to new random tsid
-func Fast() *Tsid {
+// Fast returns new random tsid
+func Fast() Tsid {
 	// Incrementing before using it
 	cnt := atomicCounter.Add(1)
 
@@ -131,16 +138,18 @@ func Fast() *Tsid {
 	return NewTsid(time | int64(tail))
 }
 
-// FromNumber returns pointer to tsid using the given number
-func FromNumber(number int64) *Tsid {
+// FromNumber returns tsid using the given number
+func FromNumber(number int64) Tsid {
 	return NewTsid(number)
 }
 
-// FromBytes returns pointer to tsid by converting the given bytes to
-// number
-func FromBytes(bytes []byte) *Tsid {
+// FromBytes returns tsid by converting the given bytes to
+// number. It returns ErrInvalidLength if the slice is not 8 bytes long.
+func FromBytes(bytes []byte) (Tsid, error) {
 
-	// TODO: Add validation
+	if len(bytes) != int(TSID_BYTES) {
+		return Nil, fmt.Errorf("%w: expected %d bytes, got %d", ErrInvalidLength, TSID_BYTES, len(bytes))
+	}
 
 	var number int64 = 0
 
@@ -153,13 +162,17 @@ func FromBytes(bytes []byte) *Tsid {
 	number |= int64(bytes[6]&0xff) << 8
 	number |= int64(bytes[7]) & 0xff
 
-	return NewTsid(int64(number))
+	return NewTsid(int64(number)), nil
 }
 
-// FromString returns pointer to tsid by converting the given string to
-// number. It validates the string before conversion.
-func FromString(str string) *Tsid {
-	arr := ToRuneArray(str)
+// FromString returns tsid by converting the given string to
+// number. It validates the string before conversion and returns an error
+// wrapping one of the parsing errors if the string is not a valid tsid.
+func FromString(str string) (Tsid, error) {
+	arr, err := ToRuneArray(str)
+	if err != nil {
+		return Nil, err
+	}
 
 	var number int64 = 0
 
@@ -177,46 +190,56 @@ func FromString(str string) *Tsid {
 	number |= ALPHABET_VALUES[arr[11]] << 5
 	number |= ALPHABET_VALUES[arr[12]]
 
-	return NewTsid(int64(number))
+	return NewTsid(int64(number)), nil
 }
 
 // ToRuneArray converts the given string to rune array. It also performs
 // validations on the rune array
-func ToRuneArray(str string) []rune {
+func ToRuneArray(str string) ([]rune, error) {
 	arr := []rune(str)
 
-	if !IsValidRuneArray(arr) {
-		return nil // TODO: Throw error
+	if err := validateRuneArray(arr); err != nil {
+		return nil, err
 	}
-	return arr
+	return arr, nil
 }
 
 // IsValidRuneArray validates the rune array.
 func IsValidRuneArray(arr []rune) bool {
+	return validateRuneArray(arr) == nil
+}
 
-	if arr == nil || len(arr) != int(TSID_CHARS) {
-		return false
-	}
+// validateRuneArray validates the rune array and returns the reason
+// why it is not a valid tsid
+func validateRuneArray(arr []rune) error {
 
-	if (ALPHABET_VALUES[arr[0]] & 0b10000) != 0 {
-		return false
+	if arr == nil || len(arr) != int(TSID_CHARS) {
+		return fmt.Errorf("%w: expected %d characters, got %d", ErrInvalidLength, TSID_CHARS, len(arr))
 	}
 
 	for i := 0; i < len(arr); i++ {
+		if arr[i] < 0 || int(arr[i]) >= len(ALPHABET_VALUES) {
+			return fmt.Errorf("%w: %q at position %d", ErrNonASCII, arr[i], i)
+		}
 		if ALPHABET_VALUES[arr[i]] == -1 {
-			return false
+			return fmt.Errorf("%w: %q at position %d", ErrInvalidCharacter, arr[i], i)
 		}
 	}
-	return true
+
+	// first character can only hold 4 bits
+	if (ALPHABET_VALUES[arr[0]] & 0b10000) != 0 {
+		return fmt.Errorf("%w: %q", ErrOverflow, arr[0])
+	}
+	return nil
 }
 
 // ToNumber returns the numerical component of the tsid
-func (t *Tsid) ToNumber() int64 {
+func (t Tsid) ToNumber() int64 {
 	return t.number
 }
 
 // ToBytes converts the number to bytes and returns the byte array
-func (t *Tsid) ToBytes() []byte {
+func (t Tsid) ToBytes() []byte {
 	bytes := make([]byte, TSID_BYTES)
 
 	bytes[0] = byte(uint64(t.number) >> 56)
@@ -234,19 +257,19 @@ func (t *Tsid) ToBytes() []byte {
 // ToString converts the number to a canonical string.
 // The output is 13 characters long and only contains characters from
 // Crockford's base32 alphabets
-func (t *Tsid) ToString() string {
+func (t Tsid) ToString() string {
 	return t.ToStringWithAlphabets(ALPHABET_UPPERCASE)
 }
 
 // ToLowerCase converts the number to a canonical string in lower case.
 // The output is 13 characters long and only contains characters from
 // Crockford's base32 alphabets
-func (t *Tsid) ToLowerCase() string {
+func (t Tsid) ToLowerCase() string {
 	return t.ToStringWithAlphabets(ALPHABET_LOWERCASE)
 }
 
 // ToStringWithAlphabets converts the number to string using the given alphabets and returns it
-func (t *Tsid) ToStringWithAlphabets(alphabets []rune) string {
+func (t Tsid) ToStringWithAlphabets(alphabets []rune) string {
 	chars := make([]rune, TSID_CHARS)
 
 	chars[0] = alphabets[((uint64(t.number) >> 60) & 0b11111)]
@@ -267,26 +290,85 @@ func (t *Tsid) ToStringWithAlphabets(alphabets []rune) string {
 }
 
 // IsValid checks if the given tsid string is valid or not
-func (t *Tsid) IsValid(str string) bool {
+func (t Tsid) IsValid(str string) bool {
 	return len(str) != 0 && IsValidRuneArray([]rune(str))
 }
 
+// IsZero checks if the tsid is the zero value Nil
+func (t Tsid) IsZero() bool {
+	return t.number == 0
+}
+
+// Compare returns -1, 0 or +1 depending on whether the tsid is less than,
+// equal to or greater than the other tsid. Tsids are compared as unsigned
+// numbers, which is consistent with ordering of their strings and bytes
+func (t Tsid) Compare(other Tsid) int {
+	a, b := uint64(t.number), uint64(other.number)
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// Before checks if the tsid sorts before the other tsid
+func (t Tsid) Before(other Tsid) bool {
+	return t.Compare(other) < 0
+}
+
+// After checks if the tsid sorts after the other tsid
+func (t Tsid) After(other Tsid) bool {
+	return t.Compare(other) > 0
+}
+
+// Equal checks if both the tsids are same
+func (t Tsid) Equal(other Tsid) bool {
+	return t == other
+}
+
+// Compare compares both the tsids. It can be used as comparator
+// with slices.SortFunc
+func Compare(a, b Tsid) int {
+	return a.Compare(b)
+}
+
+// Sort sorts the tsids in increasing order
+func Sort(tsids []Tsid) {
+	sort.Slice(tsids, func(i, j int) bool {
+		return tsids[i].Before(tsids[j])
+	})
+}
+
 // GetRandom returns random component (node + counter) of the tsid
-func (t *Tsid) GetRandom() int64 {
+func (t Tsid) GetRandom() int64 {
 	return t.number & int64(RANDOM_MASK)
 }
 
 // GetUnixMillis returns time of creation in millis since 1970-01-01
-func (t *Tsid) GetUnixMillis() int64 {
+func (t Tsid) GetUnixMillis() int64 {
 	return t.getTime() + TSID_EPOCH
 }
 
-// GetUnixMillis returns time of creation in millis since 1970-01-01
-func (t *Tsid) GetUnixMillisWithCustomEpoch(epoch int64) int64 {
+// GetUnixMillisWithCustomEpoch returns time of creation in millis since 1970-01-01
+func (t Tsid) GetUnixMillisWithCustomEpoch(epoch int64) int64 {
 	return t.getTime() + epoch
 }
 
+// GetRandomWithLayout returns random component (node + counter) of the
+// tsid generated using the given layout
+func (t Tsid) GetRandomWithLayout(layout Layout) int64 {
+	return layout.GetRandom(t)
+}
+
+// GetUnixMillisWithLayout returns time of creation in millis since 1970-01-01
+// of the tsid generated using the given layout and epoch
+func (t Tsid) GetUnixMillisWithLayout(layout Layout, epoch int64) int64 {
+	return layout.GetUnixMillis(t, epoch)
+}
+
 // getTime returns the time component
-func (t *Tsid) getTime() int64 {
+func (t Tsid) getTime() int64 {
 	return int64(uint64(t.number) >> int64(RANDOM_BITS))
 }
@@ -17,25 +17,28 @@ limitations under the License.
 package tsid
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // Lock will be used to control access for creating tsidFactory instance
 var lock = &sync.Mutex{}
 
-// Lock will be used to control synchronize access to random value generator
-var rLock = &sync.Mutex{}
-
 // Only a single instance of tsidFactory will be used per node
 var tsidFactoryInstance *TsidFactory
 
 // tsidFactory is a singleton which
 // should be used to generate random tsid
 type TsidFactory struct {
+	// mu guards counter, lastTime and access to random. Each factory
+	// has its own lock so that independent factories do not contend
+	mu sync.Mutex
+
 	node        int32
 	nodeBits    int32
 	nodeMask    int32
@@ -44,9 +47,46 @@ type TsidFactory struct {
 	counterMask int32
 	lastTime    int64
 	customEpoch int64
+	layout      Layout
 	clock       Clock
 	random      Random
 	randomBytes int32
+
+	// lock-free mode packs lastTime and counter into state as
+	// (lastTime << counterBits) | counter and advances it using CAS
+	lockFree bool
+	state    atomic.Int64
+
+	// lastClock is the latest clock reading in time units since the
+	// epoch, used to detect the clock moving backwards
+	lastClock         atomic.Int64
+	regressing        atomic.Bool
+	regressionPolicy  ClockRegressionPolicy
+	regressionLimit   time.Duration
+	regressionHandler func(ClockRegression)
+
+	overflowPolicy CounterOverflowPolicy
+	overflows      atomic.Uint64
+
+	// lease is set when the node is leased from a NodeCoordinator
+	lease *nodeLeaseKeeper
+
+	// store is set when the high-water mark is persisted. storeMark
+	// is the latest saved mark, in unix millis
+	store       StateStore
+	storeWindow time.Duration
+	storeMu     sync.Mutex
+	storeMark   atomic.Int64
+}
+
+// reservation is the result of an attempt to reserve counter values
+type reservation struct {
+	time       int64
+	counter    int32
+	count      int              // number of counter values reserved
+	wait       time.Duration    // how long to wait for the clock before retrying
+	overflow   bool             // set when waiting due to counter overflow
+	regression *ClockRegression // set when the clock starts moving backwards
 }
 
 func newTsidFactory(builder *tsidFactoryBuilder) (*TsidFactory, error) {
@@ -56,90 +96,406 @@ func newTsidFactory(builder *tsidFactoryBuilder) (*TsidFactory, error) {
 		customEpoch: builder.GetCustomEpoch(),
 		clock:       builder.GetClock(),
 		random:      builder.GetRandom(),
+		lockFree:    builder.lockFree,
+
+		regressionPolicy:  builder.regressionPolicy,
+		regressionLimit:   builder.regressionLimit,
+		regressionHandler: builder.regressionHandler,
+		overflowPolicy:    builder.overflowPolicy,
 	}
 
-	// get node bits
-	nodeBits, err := builder.GetNodeBits()
+	// get layout
+	layout, err := builder.GetLayout()
 	if err != nil {
 		log.Print(err.Error())
-		return nil, errors.New("failed to initialize tsid factory")
+		return nil, fmt.Errorf("failed to initialize tsid factory: %w", err)
 	}
-	tsidFactory.nodeBits = nodeBits
+	tsidFactory.layout = layout
+	tsidFactory.nodeBits = layout.NodeBits
 
 	// properties to be calculated
-	tsidFactory.counterBits = int32(RANDOM_BITS) - builder.nodeBits
-	tsidFactory.counterMask = int32(uint32(RANDOM_MASK) >> builder.nodeBits)
-	tsidFactory.nodeMask = int32(uint32(RANDOM_MASK) >> tsidFactory.counterBits)
+	tsidFactory.counterBits = layout.CounterBits
+	tsidFactory.counterMask = mask(layout.CounterBits)
+	tsidFactory.nodeMask = mask(layout.NodeBits)
 
 	tsidFactory.randomBytes = ((tsidFactory.counterBits - 1) / 8) + 1
 
-	// get node id
-	node, err := builder.GetNode()
+	// a window below a millisecond would save the mark in every time unit
+	if builder.store != nil && builder.storeWindow < time.Millisecond {
+		err := fmt.Errorf("state store window must be at least a millisecond: %s", builder.storeWindow)
+		log.Print(err.Error())
+		return nil, fmt.Errorf("failed to initialize tsid factory: %w", err)
+	}
+
+	// get node id, leasing it if a coordinator is provided
+	node, err := tsidFactory.getNode(builder)
 	if err != nil {
 		log.Print(err.Error())
-		return nil, errors.New("failed to initialize tsid factory")
+		return nil, fmt.Errorf("failed to initialize tsid factory: %w", err)
 	}
 	tsidFactory.node = node & int32(tsidFactory.nodeMask)
 
-	tsidFactory.lastTime = tsidFactory.clock.UnixMilli()
+	tsidFactory.lastTime = tsidFactory.elapsed(tsidFactory.clock.UnixMilli())
 	randomNumber, err := tsidFactory.getRandomValue()
 	if err != nil {
+		tsidFactory.Close()
 		return nil, err
 	}
 
 	tsidFactory.counter = randomNumber
+	tsidFactory.state.Store(tsidFactory.lastTime<<tsidFactory.counterBits | int64(randomNumber))
+	tsidFactory.lastClock.Store(tsidFactory.lastTime)
+
+	// continue after the high-water mark of a previous run
+	if builder.store != nil {
+		if err := tsidFactory.loadState(builder.store, builder.storeWindow); err != nil {
+			tsidFactory.Close()
+			return nil, fmt.Errorf("failed to initialize tsid factory: %w", err)
+		}
+	}
 	return tsidFactory, nil
 }
 
+// getNode leases the node from the coordinator of the builder, unless
+// the node is provided using WithNode
+func (factory *TsidFactory) getNode(builder *tsidFactoryBuilder) (int32, error) {
+	if builder.coordinator == nil || builder.nodeSet || factory.nodeBits == 0 {
+		return builder.GetNode()
+	}
+
+	lease, err := acquireNodeLease(builder.coordinator, time.Now, factory.nodeBits, builder.leaseTTL)
+	if err != nil {
+		return 0, err
+	}
+	factory.lease = lease
+	return lease.node, nil
+}
+
+// Close saves the high-water mark of the last generated tsid, and stops
+// renewing the node lease and releases it. A factory which leases its node
+// returns ErrLeaseLost once closed. Close must be called once the factory
+// is no longer used
+func (factory *TsidFactory) Close() error {
+	var errs []error
+	if factory.store != nil {
+		errs = append(errs, factory.saveState())
+	}
+	if factory.lease != nil {
+		errs = append(errs, factory.lease.close())
+	}
+	return errors.Join(errs...)
+}
+
 // Generate will return a tsid with random number
-func (factory *TsidFactory) Generate() (*Tsid, error) {
-	time, err := factory.getTime()
+func (factory *TsidFactory) Generate() (Tsid, error) {
+	return factory.GenerateContext(context.Background())
+}
+
+// GenerateContext returns a tsid like Generate. When the factory has to
+// wait for the clock, e.g. using CounterOverflowWait, it stops waiting and
+// returns the context error once the context is done
+func (factory *TsidFactory) GenerateContext(ctx context.Context) (Tsid, error) {
+	time, counter, _, err := factory.reserve(ctx, 1)
 	if err != nil {
-		return nil, err
+		return Nil, err
 	}
 
-	time = time << RANDOM_BITS
-	node := factory.node << factory.counterBits
-	counter := factory.counter & factory.counterMask
+	return factory.layout.compose(time, factory.node, counter), nil
+}
 
-	tsidNumber := int64(time | int64(node) | int64(counter))
-	return NewTsid(tsidNumber), nil
+// GenerateN returns n strictly increasing tsids. The counter values are
+// reserved at once, rolling over into the next time units when the counter
+// is exhausted
+func (factory *TsidFactory) GenerateN(n int) ([]Tsid, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("invalid number of tsids: %d", n)
+	}
+	return factory.AppendGenerate(make([]Tsid, 0, n), n)
 }
 
-func (factory *TsidFactory) getTime() (int64, error) {
-	time := factory.clock.UnixMilli()
+// AppendGenerate generates n strictly increasing tsids like GenerateN
+// and appends them to dst. Using CounterOverflowWait, the counter values
+// are reserved one time unit at a time
+func (factory *TsidFactory) AppendGenerate(dst []Tsid, n int) ([]Tsid, error) {
+	if n < 0 {
+		return dst, fmt.Errorf("invalid number of tsids: %d", n)
+	}
+
+	for n > 0 {
+		time, counter, count, err := factory.reserve(context.Background(), n)
+		if err != nil {
+			return dst, err
+		}
+
+		for i := 0; i < count; i++ {
+			t, c := factory.advance(time, counter, int64(i))
+			dst = append(dst, factory.layout.compose(t, factory.node, c))
+		}
+		n -= count
+	}
+	return dst, nil
+}
+
+// GetLayout returns the layout used by the factory
+func (factory *TsidFactory) GetLayout() Layout {
+	return factory.layout
+}
+
+// CounterOverflows returns how many times the counter was exhausted
+// within a time unit, either borrowing the next time unit or waiting for it
+func (factory *TsidFactory) CounterOverflows() uint64 {
+	return factory.overflows.Load()
+}
+
+// reserve reserves up to n consecutive counter values and returns the time
+// component, in time units since the epoch, and the counter of the first
+// one along with the number of values reserved. It waits for the clock
+// when required by the regression or counter overflow policy
+func (factory *TsidFactory) reserve(ctx context.Context, n int) (int64, int32, int, error) {
+	var waited time.Duration
+	var overflowed bool
+	for {
+		if err := ctx.Err(); err != nil {
+			return 0, 0, 0, err
+		}
+		if factory.lease != nil {
+			if err := factory.lease.check(); err != nil {
+				return 0, 0, 0, err
+			}
+		}
+
+		r, err := factory.tryReserve(n)
+		if r.regression != nil && factory.regressionHandler != nil {
+			factory.regressionHandler(*r.regression)
+		}
+
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		if r.wait == 0 {
+			last, _ := factory.advance(r.time, r.counter, int64(r.count-1))
+			if err := factory.checkTime(r.time, last); err != nil {
+				return 0, 0, 0, err
+			}
+
+			if factory.store != nil {
+				if err := factory.persistState(last); err != nil {
+					return 0, 0, 0, err
+				}
+			}
+			return r.time, r.counter, r.count, nil
+		}
+
+		// count the overflow once, however many times it waits
+		if r.overflow && !overflowed {
+			factory.overflows.Add(1)
+			overflowed = true
+		}
+		if !r.overflow {
+			waited += r.wait
+			if waited > factory.regressionLimit {
+				return 0, 0, 0, fmt.Errorf("%w: waited %s, more than max wait %s",
+					ErrClockMovedBackwards, waited, factory.regressionLimit)
+			}
+		}
+
+		if err := sleep(ctx, r.wait); err != nil {
+			return 0, 0, 0, err
+		}
+	}
+}
+
+// tryReserve makes a single attempt to reserve up to n consecutive counter
+// values. The state is read and updated under the lock so that concurrent
+// calls never share them
+func (factory *TsidFactory) tryReserve(n int) (reservation, error) {
+	if factory.lockFree {
+		return factory.tryReserveLockFree(n)
+	}
+
+	factory.mu.Lock()
+	defer factory.mu.Unlock()
+
+	lastClock := factory.lastClock.Load()
+	millis := factory.clock.UnixMilli()
+	time := factory.elapsed(millis)
+
+	r, err := factory.observeClock(lastClock, time)
+	if err != nil || r.wait > 0 {
+		return r, err
+	}
+	if time > lastClock {
+		factory.lastClock.Store(time)
+	}
+
+	first, counter := time, int32(0)
 	if time <= factory.lastTime {
-		lock.Lock()
-		factory.counter++
-		carry := uint32(factory.counter) >> factory.counterBits
-		factory.counter = factory.counter & factory.counterMask
-		time = factory.lastTime + int64(carry)
-		lock.Unlock()
+		first, counter = factory.advance(factory.lastTime, factory.counter, 1)
 
 	} else {
 		value, err := factory.getRandomValue()
 		if err != nil {
-			return 0, err
+			return r, err
+		}
+		counter = value
+	}
+
+	current := factory.lastTime
+	if time > current {
+		current = time
+	}
+
+	r.count, r.wait = factory.checkOverflow(n, first, counter, current, millis)
+	if r.wait > 0 {
+		r.overflow = true
+		return r, nil
+	}
+
+	factory.lastTime, factory.counter = factory.advance(first, counter, int64(r.count-1))
+	if factory.lastTime > current {
+		factory.overflows.Add(1)
+	}
+
+	r.time, r.counter = first, counter
+	return r, nil
+}
+
+// tryReserveLockFree is the lock-free variant of tryReserve. Adding to
+// the packed state carries counter overflow into the time component,
+// which borrows the next time units like tryReserve does
+func (factory *TsidFactory) tryReserveLockFree(n int) (reservation, error) {
+	for {
+		// lastClock must be loaded before reading the clock, otherwise
+		// a concurrent reading could be mistaken for a regression
+		lastClock := factory.lastClock.Load()
+		state := factory.state.Load()
+		lastTime := state >> factory.counterBits
+		millis := factory.clock.UnixMilli()
+		time := factory.elapsed(millis)
+
+		r, err := factory.observeClock(lastClock, time)
+		if err != nil || r.wait > 0 {
+			return r, err
+		}
+		if time > lastClock {
+			factory.lastClock.CompareAndSwap(lastClock, time)
+		}
+
+		// a time beyond the time bits would overflow the packed state
+		if err := factory.checkTime(time, time); err != nil {
+			return r, err
+		}
+
+		first := state + 1
+		if time > lastTime {
+			value, err := factory.getRandomValue()
+			if err != nil {
+				return r, err
+			}
+			first = time<<factory.counterBits | int64(value)
+		}
+
+		current := lastTime
+		if time > current {
+			current = time
+		}
+
+		firstTime, counter := first>>factory.counterBits, int32(first)&factory.counterMask
+		r.count, r.wait = factory.checkOverflow(n, firstTime, counter, current, millis)
+		if r.wait > 0 {
+			r.overflow = true
+			return r, nil
 		}
-		lock.Lock()
-		factory.counter = value
-		lock.Unlock()
+
+		last := first + int64(r.count-1)
+		if factory.state.CompareAndSwap(state, last) {
+			if last>>factory.counterBits > current {
+				factory.overflows.Add(1)
+			}
+
+			r.time, r.counter = firstTime, counter
+			return r, nil
+		}
+	}
+}
+
+// checkTime checks that the reserved time components, in time units
+// since the epoch, fit in the time bits of the layout
+func (factory *TsidFactory) checkTime(first int64, last int64) error {
+	if first < 0 {
+		return fmt.Errorf("%w: clock is %d time units before epoch", ErrTimeBeforeEpoch, -first)
+	}
+	if last > factory.layout.maxTime() {
+		return fmt.Errorf("%w of %d bits: %d time units since epoch", ErrTimeOutOfRange, factory.layout.TimeBits, last)
 	}
-	factory.lastTime = time
-	return (time - factory.customEpoch), nil
+	return nil
+}
+
+// checkOverflow applies the counter overflow policy to a reservation of
+// n counter values starting from the first time and counter. The current
+// time unit is the latest of the clock and the last reserved time. It
+// returns how many values can be reserved, or how long to wait for the
+// clock to reach the first time
+func (factory *TsidFactory) checkOverflow(n int, first int64, counter int32, current int64, millis int64) (int, time.Duration) {
+	if factory.overflowPolicy != CounterOverflowWait {
+		return n, 0
+	}
+
+	if first > current {
+		// wait until the clock reaches the start of the time unit
+		start := first*factory.layout.unitMillis() + factory.customEpoch
+		return 0, durationMillis(start - millis)
+	}
+
+	// only reserve the counter values left in the time unit
+	if available := int64(factory.counterMask) - int64(counter) + 1; int64(n) > available {
+		return int(available), 0
+	}
+	return n, 0
+}
+
+// observeClock compares the clock reading with the last one and applies
+// the regression policy if the clock moved backwards
+func (factory *TsidFactory) observeClock(lastClock int64, time int64) (reservation, error) {
+	r := reservation{}
+	if time >= lastClock {
+		factory.regressing.Store(false)
+		return r, nil
+	}
+
+	regression := factory.newClockRegression(lastClock, time)
+	if factory.regressing.CompareAndSwap(false, true) {
+		r.regression = &regression
+	}
+
+	wait, err := factory.checkRegression(regression.Drift)
+	r.wait = wait
+	return r, err
+}
+
+// advance returns the time and counter after incrementing the counter by
+// delta. Counter overflow is carried into the time component
+func (factory *TsidFactory) advance(time int64, counter int32, delta int64) (int64, int32) {
+	total := int64(counter) + delta
+	return time + total>>factory.counterBits, int32(total & int64(factory.counterMask))
+}
+
+// elapsed converts unix millis to time units since the epoch
+func (factory *TsidFactory) elapsed(millis int64) int64 {
+	return (millis - factory.customEpoch) / factory.layout.unitMillis()
 }
 
 func (factory *TsidFactory) getRandomValue() (int32, error) {
 	return factory.getRandomCounter()
 }
 
+// getRandomCounter returns a random counter value. Byte randoms are asked
+// for as many bytes as the counter needs, any other random for an int
 func (factory *TsidFactory) getRandomCounter() (int32, error) {
 	switch factory.random.(type) {
 	case *byteRandom:
 		{
-			rLock.Lock()
 			bytes, err := factory.random.NextBytes(factory.randomBytes)
-			rLock.Unlock()
 
 			if err != nil {
 				return 0, err
@@ -153,13 +509,14 @@ func (factory *TsidFactory) getRandomCounter() (int32, error) {
 			case 3:
 				return ((int32(bytes[0]&0xff) << 16) | (int32(bytes[1]&0xff) << 8) |
 					int32(bytes[2]&0xff)) & factory.counterMask, nil
+			case 4:
+				return ((int32(bytes[0]&0xff) << 24) | (int32(bytes[1]&0xff) << 16) |
+					(int32(bytes[2]&0xff) << 8) | int32(bytes[3]&0xff)) & factory.counterMask, nil
 			}
 		}
-	case *intRandom:
+	default:
 		{
-			rLock.Lock()
 			value, err := factory.random.NextInt()
-			rLock.Unlock()
 			if err != nil {
 				return 0, err
 			}
@@ -168,15 +525,31 @@ func (factory *TsidFactory) getRandomCounter() (int32, error) {
 		}
 	}
 
-	return 0, errors.New("invalid random")
+	return 0, errors.New("invalid random bytes")
 }
 
 type tsidFactoryBuilder struct {
-	node        int32
-	nodeBits    int32
-	customEpoch int64
-	clock       Clock
-	random      Random
+	node          int32
+	nodeSet       bool
+	nodeResolvers []NodeResolver
+	nodeBits      int32
+	layout        *Layout
+	customEpoch   int64
+	clock         Clock
+	random        Random
+	lockFree      bool
+
+	regressionPolicy  ClockRegressionPolicy
+	regressionLimit   time.Duration
+	regressionHandler func(ClockRegression)
+
+	overflowPolicy CounterOverflowPolicy
+
+	coordinator NodeCoordinator
+	leaseTTL    time.Duration
+
+	store       StateStore
+	storeWindow time.Duration
 }
 
 // TsidFactoryBuilder should be used to get instance of tsidFactory
@@ -186,6 +559,41 @@ func TsidFactoryBuilder() *tsidFactoryBuilder {
 
 func (builder *tsidFactoryBuilder) WithNode(node int32) *tsidFactoryBuilder {
 	builder.node = node
+	builder.nodeSet = true
+	return builder
+}
+
+// WithNodeResolvers sets the resolvers used to find the node id when it
+// is not provided using WithNode. They are tried in the given order, and
+// the first one which does not return ErrNodeNotResolved is used
+func (builder *tsidFactoryBuilder) WithNodeResolvers(resolvers ...NodeResolver) *tsidFactoryBuilder {
+	builder.nodeResolvers = resolvers
+	return builder
+}
+
+// WithNodeCoordinator leases the node id from the coordinator when it is
+// not provided using WithNode. The lease is renewed in the background
+// until the factory is closed, and the factory stops generating tsids
+// with ErrLeaseLost if the lease is lost. The ttl must be at least a
+// millisecond
+func (builder *tsidFactoryBuilder) WithNodeCoordinator(coordinator NodeCoordinator, ttl time.Duration) *tsidFactoryBuilder {
+	builder.coordinator = coordinator
+	builder.leaseTTL = ttl
+	return builder
+}
+
+// WithStateStore persists the high-water mark of the factory, so that it
+// does not generate tsids below the ones of a previous run. The mark is
+// saved one window ahead of the generated tsids, and at Close. At startup,
+// a clock behind the mark is handled by the clock regression policy.
+//
+// The window trades durability for throughput: the store is saved
+// synchronously at most once per window, and a restart after a crash
+// skips up to a window of time. The window must be at least a millisecond,
+// a second suits most stores
+func (builder *tsidFactoryBuilder) WithStateStore(store StateStore, window time.Duration) *tsidFactoryBuilder {
+	builder.store = store
+	builder.storeWindow = window
 	return builder
 }
 
@@ -194,6 +602,13 @@ func (builder *tsidFactoryBuilder) WithNodeBits(nodeBits int32) *tsidFactoryBuil
 	return builder
 }
 
+// WithLayout sets the bit layout of the generated tsids. Node bits of the
+// layout take precedence over WithNodeBits
+func (builder *tsidFactoryBuilder) WithLayout(layout Layout) *tsidFactoryBuilder {
+	builder.layout = &layout
+	return builder
+}
+
 func (builder *tsidFactoryBuilder) WithCustomEpoch(customEpoch int64) *tsidFactoryBuilder {
 	builder.customEpoch = customEpoch
 	return builder
@@ -209,23 +624,83 @@ func (builder *tsidFactoryBuilder) WithRandom(random Random) *tsidFactoryBuilder
 	return builder
 }
 
-// GetNode returns the provided node id. Default is zero.
+// WithLockFree enables the lock-free generator, which advances time and
+// counter using atomic compare-and-swap instead of a mutex. Clock and
+// random must be safe for concurrent use, and the time since epoch must
+// fit in 63 - counterBits bits
+func (builder *tsidFactoryBuilder) WithLockFree(lockFree bool) *tsidFactoryBuilder {
+	builder.lockFree = lockFree
+	return builder
+}
+
+// WithClockRegressionPolicy sets what to do when the clock moves backwards.
+// The limit is the max wait for ClockRegressionWait, and the threshold
+// for ClockRegressionBorrowThenFail. Default is ClockRegressionBorrow
+func (builder *tsidFactoryBuilder) WithClockRegressionPolicy(policy ClockRegressionPolicy, limit time.Duration) *tsidFactoryBuilder {
+	builder.regressionPolicy = policy
+	builder.regressionLimit = limit
+	return builder
+}
+
+// WithClockRegressionHandler sets a callback which is called once every
+// time the clock starts moving backwards, e.g. to raise an alert
+func (builder *tsidFactoryBuilder) WithClockRegressionHandler(handler func(ClockRegression)) *tsidFactoryBuilder {
+	builder.regressionHandler = handler
+	return builder
+}
+
+// WithCounterOverflowPolicy sets what to do when the counter is exhausted
+// within a time unit. Default is CounterOverflowBorrow
+func (builder *tsidFactoryBuilder) WithCounterOverflowPolicy(policy CounterOverflowPolicy) *tsidFactoryBuilder {
+	builder.overflowPolicy = policy
+	return builder
+}
+
+// GetNode returns the provided node id, or else the node id from the
+// resolvers. Default is zero.
 func (builder *tsidFactoryBuilder) GetNode() (int32, error) {
-	if builder.nodeBits <= 0 {
+	nodeBits, err := builder.GetNodeBits()
+	if err != nil {
+		return 0, err
+	}
+
+	if nodeBits <= 0 {
 		return 0, nil
 	}
-	max := int32(1<<builder.nodeBits) - 1
+	max := mask(nodeBits)
 
-	if builder.node < 0 || builder.node > max {
-		err := fmt.Sprintf("node id out of range [0, %d]: %d", max, builder.node)
-		return 0, errors.New(err)
+	node := builder.node
+	if !builder.nodeSet && len(builder.nodeResolvers) > 0 {
+		node, err = builder.resolveNode(nodeBits)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if node < 0 || node > max {
+		return 0, fmt.Errorf("%w [0, %d]: %d", ErrNodeOutOfRange, max, node)
 	}
-	return builder.node, nil
+	return node, nil
+}
+
+// resolveNode returns the node id from the first resolver which applies
+func (builder *tsidFactoryBuilder) resolveNode(nodeBits int32) (int32, error) {
+	for _, resolver := range builder.nodeResolvers {
+		node, err := resolver.ResolveNode(nodeBits)
+		if errors.Is(err, ErrNodeNotResolved) {
+			continue
+		}
+		return node, err
+	}
+	return 0, fmt.Errorf("%w: no resolver applies", ErrNodeNotResolved)
 }
 
 // GetNodeBits returns the provided node bits. Default is zero.
-// Range: [0, 20]
+// Range: [0, 20], or the node bits of the layout if provided
 func (builder *tsidFactoryBuilder) GetNodeBits() (int32, error) {
+	if builder.layout != nil {
+		return builder.layout.NodeBits, nil
+	}
 	max := 20
 
 	if builder.nodeBits < 0 || builder.nodeBits > 20 {
@@ -235,9 +710,28 @@ func (builder *tsidFactoryBuilder) GetNodeBits() (int32, error) {
 	return builder.nodeBits, nil
 }
 
+// GetLayout returns the provided layout. Default is DefaultLayout with
+// the provided node bits
+func (builder *tsidFactoryBuilder) GetLayout() (Layout, error) {
+	if builder.layout != nil {
+		return *builder.layout, builder.layout.Validate()
+	}
+
+	nodeBits, err := builder.GetNodeBits()
+	if err != nil {
+		return Layout{}, err
+	}
+
+	layout := DefaultLayout
+	layout.NodeBits = nodeBits
+	layout.CounterBits = RANDOM_BITS - nodeBits
+	return layout, nil
+}
+
+// GetClock returns the provided clock. Default is the system clock
 func (builder *tsidFactoryBuilder) GetClock() Clock {
 	if builder.clock == nil {
-		builder.clock = time.Now().UTC()
+		builder.clock = NewSystemClock()
 	}
 	return builder.clock
 }
@@ -259,10 +753,6 @@ func (builder *tsidFactoryBuilder) GetCustomEpoch() int64 {
 }
 
 func (builder *tsidFactoryBuilder) Build() (*TsidFactory, error) {
-	if tsidFactoryInstance != nil {
-		return tsidFactoryInstance, nil
-	}
-
 	lock.Lock()
 	defer lock.Unlock()
 
@@ -17,6 +17,7 @@ limitations under the License.
 package tsid
 
 import (
+	"sync"
 	"testing"
 	"time"
 
@@ -40,7 +41,7 @@ func Test_WithNode(t *testing.T) {
 			assert.NotNil(t, tsidFactory)
 
 			tsid, _ := tsidFactory.Generate()
-			assert.NotNil(t, tsid)
+			assert.False(t, tsid.IsZero())
 
 			actualNode := int32(uint32(tsid.GetRandom())>>shift) & int32(mask)
 			assert.Equal(t, node, actualNode, "Node id does not match the provided id")
@@ -60,7 +61,7 @@ func Test_WithNode(t *testing.T) {
 			assert.NotNil(t, tsidFactory)
 
 			tsid, _ := tsidFactory.Generate()
-			assert.NotNil(t, tsid)
+			assert.False(t, tsid.IsZero())
 
 			actualNode := int32(uint32(tsid.GetRandom())>>shift) & int32(mask)
 			assert.Zero(t, actualNode, "Node id does not match the default id")
@@ -86,7 +87,7 @@ func Test_WithNodeBits(t *testing.T) {
 			assert.NotNil(t, tsidFactory)
 
 			tsid, _ := tsidFactory.Generate()
-			assert.NotNil(t, tsid)
+			assert.False(t, tsid.IsZero())
 
 			actualNode := int32(uint32(tsid.GetRandom())>>shift) & int32(mask)
 			assert.Equal(t, node, actualNode, "Node id does not match the provided id")
@@ -107,7 +108,7 @@ func Test_WithNodeBits(t *testing.T) {
 			assert.NotNil(t, tsidFactory)
 
 			tsid, _ := tsidFactory.Generate()
-			assert.NotNil(t, tsid)
+			assert.False(t, tsid.IsZero())
 
 			actualNode := int32(uint32(tsid.GetRandom())>>shift) & int32(mask)
 			assert.Zero(t, actualNode, "Node id does not match the default id")
@@ -224,6 +225,239 @@ func Test_Generate(t *testing.T) {
 	})
 }
 
+func Test_GenerateConcurrently(t *testing.T) {
+
+	t.Run("given shared factory goroutines should not generate same tsid", func(t *testing.T) {
+		goroutineCount := 8
+		iterationCount := 10_000
+
+		tsidFactory, err := TsidFactoryBuilder().
+			NewInstance()
+		assert.Nil(t, err)
+
+		results := make([][]Tsid, goroutineCount)
+		wg := &sync.WaitGroup{}
+
+		for i := 0; i < goroutineCount; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < iterationCount; j++ {
+					tsid, err := tsidFactory.Generate()
+					assert.Nil(t, err)
+					results[i] = append(results[i], tsid)
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		seen := make(map[Tsid]struct{}, goroutineCount*iterationCount)
+		for _, tsids := range results {
+			for j, tsid := range tsids {
+				_, ok := seen[tsid]
+				assert.False(t, ok, "Collision detected")
+				seen[tsid] = struct{}{}
+
+				// each goroutine should observe increasing tsids
+				if j > 0 {
+					assert.True(t, tsids[j-1].Before(tsid))
+				}
+			}
+		}
+	})
+
+	t.Run("given independent factories should generate concurrently", func(t *testing.T) {
+		goroutineCount := 8
+		wg := &sync.WaitGroup{}
+
+		for i := 0; i < goroutineCount; i++ {
+			wg.Add(1)
+			go func(node int32) {
+				defer wg.Done()
+
+				tsidFactory, err := TsidFactoryBuilder().
+					WithNodeBits(NODE_BITS_1024).
+					WithNode(node).
+					NewInstance()
+				assert.Nil(t, err)
+
+				for j := 0; j < 10_000; j++ {
+					_, err := tsidFactory.Generate()
+					assert.Nil(t, err)
+				}
+			}(int32(i))
+		}
+		wg.Wait()
+	})
+}
+
+func Test_WithLockFree(t *testing.T) {
+
+	t.Run("given clock when clock drifts time should not decrease", func(t *testing.T) {
+
+		var diff int64 = 10000
+		epoch := time.Now().UnixMilli()
+
+		clock := &MockClock{
+			millis: []int64{epoch, epoch, epoch + 0, epoch + 1, epoch + 2, epoch + 3 - diff, epoch + 5},
+		}
+
+		intRandom := NewIntRandomWithSupplierFunc(func() (int32, error) {
+			return 0, nil
+		})
+
+		tsidFactory, _ := TsidFactoryBuilder().
+			WithClock(clock).
+			WithRandom(intRandom).
+			WithLockFree(true).
+			NewInstance()
+		assert.NotNil(t, tsidFactory)
+
+		var millis []int64
+		for i := 0; i < 6; i++ {
+			tsid, err := tsidFactory.Generate()
+			assert.Nil(t, err)
+			millis = append(millis, tsid.GetUnixMillis())
+		}
+
+		assert.Equal(t, []int64{epoch, epoch, epoch + 1, epoch + 2, epoch + 2, epoch + 5}, millis)
+	})
+
+	t.Run("given counter overflow should borrow next millisecond", func(t *testing.T) {
+		now := time.Now()
+
+		intRandom := NewIntRandomWithSupplierFunc(func() (int32, error) {
+			return 0, nil
+		})
+
+		tsidFactory, _ := TsidFactoryBuilder().
+			WithNodeBits(20).
+			WithClock(now).
+			WithRandom(intRandom).
+			WithLockFree(true).
+			NewInstance()
+		assert.NotNil(t, tsidFactory)
+
+		// counter has 2 bits, 4 tsids can be generated per millisecond
+		last := Nil
+		for i := 1; i <= 8; i++ {
+			tsid, err := tsidFactory.Generate()
+			assert.Nil(t, err)
+			assert.True(t, last.Before(tsid))
+			assert.Equal(t, now.UnixMilli()+int64(i/4), tsid.GetUnixMillis())
+			last = tsid
+		}
+	})
+
+	t.Run("given shared factory goroutines should not generate same tsid", func(t *testing.T) {
+		goroutineCount := 8
+		iterationCount := 10_000
+
+		tsidFactory, err := TsidFactoryBuilder().
+			WithLockFree(true).
+			NewInstance()
+		assert.Nil(t, err)
+
+		results := make([][]Tsid, goroutineCount)
+		wg := &sync.WaitGroup{}
+
+		for i := 0; i < goroutineCount; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < iterationCount; j++ {
+					tsid, err := tsidFactory.Generate()
+					assert.Nil(t, err)
+					results[i] = append(results[i], tsid)
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		seen := make(map[Tsid]struct{}, goroutineCount*iterationCount)
+		for _, tsids := range results {
+			for j, tsid := range tsids {
+				_, ok := seen[tsid]
+				assert.False(t, ok, "Collision detected")
+				seen[tsid] = struct{}{}
+
+				if j > 0 {
+					assert.True(t, tsids[j-1].Before(tsid))
+				}
+			}
+		}
+	})
+}
+
+func Test_GenerateN(t *testing.T) {
+
+	t.Run("should generate strictly increasing tsids", func(t *testing.T) {
+		for _, lockFree := range []bool{false, true} {
+			now := time.Now()
+
+			intRandom := NewIntRandomWithSupplierFunc(func() (int32, error) {
+				return 0, nil
+			})
+
+			// counter has 4 bits, 16 tsids can be generated per millisecond
+			tsidFactory, err := TsidFactoryBuilder().
+				WithNodeBits(18).
+				WithNode(5).
+				WithClock(now).
+				WithRandom(intRandom).
+				WithLockFree(lockFree).
+				NewInstance()
+			assert.Nil(t, err)
+
+			tsids, err := tsidFactory.GenerateN(100)
+			assert.Nil(t, err)
+			assert.Len(t, tsids, 100)
+
+			for i, tsid := range tsids {
+				components := tsidFactory.Decode(tsid)
+				assert.Equal(t, now.UnixMilli()+int64((i+1)/16), components.Time.UnixMilli())
+				assert.Equal(t, int32((i+1)%16), components.Counter)
+				assert.Equal(t, int32(5), components.Node)
+
+				if i > 0 {
+					assert.True(t, tsids[i-1].Before(tsid))
+				}
+			}
+
+			// next tsid should continue after the reserved range
+			tsid, err := tsidFactory.Generate()
+			assert.Nil(t, err)
+			assert.True(t, tsids[99].Before(tsid))
+			assert.Equal(t, int32(101%16), tsidFactory.Decode(tsid).Counter)
+		}
+	})
+
+	t.Run("given destination should append tsids", func(t *testing.T) {
+		tsidFactory, err := TsidFactoryBuilder().
+			NewInstance()
+		assert.Nil(t, err)
+
+		first := Fast()
+		tsids, err := tsidFactory.AppendGenerate([]Tsid{first}, 10)
+		assert.Nil(t, err)
+		assert.Len(t, tsids, 11)
+		assert.Equal(t, first, tsids[0])
+
+		tsids, err = tsidFactory.AppendGenerate(tsids, 0)
+		assert.Nil(t, err)
+		assert.Len(t, tsids, 11)
+	})
+
+	t.Run("given negative count should return error", func(t *testing.T) {
+		tsidFactory, err := TsidFactoryBuilder().
+			NewInstance()
+		assert.Nil(t, err)
+
+		_, err = tsidFactory.GenerateN(-1)
+		assert.NotNil(t, err)
+	})
+}
+
 type MockClock struct {
 	index  int
 	millis []int64
@@ -17,6 +17,7 @@ limitations under the License.
 package tsid
 
 import (
+	"errors"
 	"math"
 	"math/rand"
 	"testing"
@@ -39,7 +40,7 @@ func Test_GetUnixMillis(t *testing.T) {
 		assert.NotNil(t, tsidFactory)
 
 		tsid, _ := tsidFactory.Generate()
-		assert.NotNil(t, tsid)
+		assert.False(t, tsid.IsZero())
 
 		middle := tsid.GetUnixMillis()
 		end := time.Now().UnixMilli()
@@ -76,7 +77,7 @@ func Test_GetUnixMillis(t *testing.T) {
 			assert.NotNil(t, tsidFactory)
 
 			tsid, _ := tsidFactory.Generate()
-			assert.NotNil(t, tsid)
+			assert.False(t, tsid.IsZero())
 
 			result := tsid.GetUnixMillis()
 			assert.Equal(t, millis, result)
@@ -96,7 +97,7 @@ func Test_GetUnixMillis(t *testing.T) {
 		assert.NotNil(t, tsidFactory)
 
 		tsid, _ := tsidFactory.Generate()
-		assert.NotNil(t, tsid)
+		assert.False(t, tsid.IsZero())
 
 		middle := tsid.GetUnixMillisWithCustomEpoch(epoch)
 		end := time.Now().UnixMilli()
@@ -106,3 +107,104 @@ func Test_GetUnixMillis(t *testing.T) {
 		}
 	})
 }
+
+func Test_FromString(t *testing.T) {
+
+	t.Run("given valid string should return same tsid", func(t *testing.T) {
+		for i := 0; i < LOOP_MAX; i++ {
+			expected := Fast()
+
+			tsid, err := FromString(expected.ToString())
+			assert.Nil(t, err)
+			assert.Equal(t, expected.ToNumber(), tsid.ToNumber())
+
+			tsid, err = FromString(expected.ToLowerCase())
+			assert.Nil(t, err)
+			assert.Equal(t, expected.ToNumber(), tsid.ToNumber())
+		}
+	})
+
+	t.Run("given invalid string should return error", func(t *testing.T) {
+		cases := []struct {
+			str string
+			err error
+		}{
+			{"", ErrInvalidLength},
+			{"0123456789AB", ErrInvalidLength},
+			{"0123456789ABCD", ErrInvalidLength},
+			{"0123456789AB#", ErrInvalidCharacter},
+			{"0123456789ABU", ErrInvalidCharacter},
+			{"G123456789ABC", ErrOverflow},
+			{"Z123456789ABC", ErrOverflow},
+			{"0123456789AB\u00e9", ErrNonASCII},
+			{"0123456789AB\u65e5", ErrNonASCII},
+		}
+
+		for _, c := range cases {
+			tsid, err := FromString(c.str)
+			assert.Equal(t, Nil, tsid)
+			assert.True(t, errors.Is(err, c.err), "expected %v for %q, got %v", c.err, c.str, err)
+		}
+	})
+}
+
+func Test_FromBytes(t *testing.T) {
+
+	t.Run("given valid bytes should return same tsid", func(t *testing.T) {
+		for i := 0; i < LOOP_MAX; i++ {
+			expected := Fast()
+
+			tsid, err := FromBytes(expected.ToBytes())
+			assert.Nil(t, err)
+			assert.Equal(t, expected.ToNumber(), tsid.ToNumber())
+		}
+	})
+
+	t.Run("given invalid length should return error", func(t *testing.T) {
+		for _, bytes := range [][]byte{nil, {}, make([]byte, 7), make([]byte, 9)} {
+			tsid, err := FromBytes(bytes)
+			assert.Equal(t, Nil, tsid)
+			assert.True(t, errors.Is(err, ErrInvalidLength))
+		}
+	})
+}
+
+func Test_Compare(t *testing.T) {
+
+	t.Run("should compare tsids", func(t *testing.T) {
+		a := FromNumber(1)
+		b := FromNumber(2)
+		c := FromNumber(-1) // greatest unsigned value
+
+		assert.Equal(t, -1, a.Compare(b))
+		assert.Equal(t, 1, b.Compare(a))
+		assert.Equal(t, 0, a.Compare(FromNumber(1)))
+		assert.Equal(t, -1, Compare(b, c))
+
+		assert.True(t, a.Before(b))
+		assert.True(t, c.After(b))
+		assert.True(t, a.Equal(FromNumber(1)))
+		assert.True(t, a == FromNumber(1))
+	})
+
+	t.Run("should be consistent with string ordering", func(t *testing.T) {
+		tsids := make([]Tsid, LOOP_MAX)
+		random := rand.New(rand.NewSource(time.Now().UnixNano()))
+		for i := range tsids {
+			tsids[i] = FromNumber(int64(random.Uint64()))
+		}
+
+		Sort(tsids)
+		for i := 1; i < len(tsids); i++ {
+			assert.False(t, tsids[i].Before(tsids[i-1]))
+			assert.LessOrEqual(t, tsids[i-1].ToString(), tsids[i].ToString())
+		}
+	})
+
+	t.Run("zero value should be nil", func(t *testing.T) {
+		var tsid Tsid
+		assert.True(t, tsid.IsZero())
+		assert.Equal(t, Nil, tsid)
+		assert.False(t, Fast().IsZero())
+	})
+}
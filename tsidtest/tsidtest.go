@@ -0,0 +1,133 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tsidtest provides a clock and a random which can be controlled
+// by tests, so that generated tsids are reproducible
+package tsidtest
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	tsid "github.com/vishal-bihani/go-tsid"
+)
+
+// FakeClock is a tsid.Clock which only moves when it is set or advanced.
+// It is safe for concurrent use
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFakeClock returns a clock set to the given time
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{
+		now: now,
+	}
+}
+
+// UnixMilli returns the time of the clock in millis since 1970-01-01
+func (c *FakeClock) UnixMilli() int64 {
+	return c.Now().UnixMilli()
+}
+
+// Now returns the time of the clock
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// Set sets the time of the clock. It can be used to move the clock
+// backwards
+func (c *FakeClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = now
+}
+
+// Advance moves the clock by the given duration
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// SequenceRandom is a tsid.Random which returns the given values in order,
+// starting over after the last one. It is safe for concurrent use
+type SequenceRandom struct {
+	mu     sync.Mutex
+	values []int32
+	index  int
+}
+
+// NewSequenceRandom returns a random which returns the given values in
+// order. Without values it always returns zero
+func NewSequenceRandom(values ...int32) *SequenceRandom {
+	if len(values) == 0 {
+		values = []int32{0}
+	}
+
+	return &SequenceRandom{
+		values: values,
+	}
+}
+
+// NextInt returns the next value of the sequence
+func (r *SequenceRandom) NextInt() (int32, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	value := r.values[r.index]
+	r.index = (r.index + 1) % len(r.values)
+
+	return value, nil
+}
+
+// NextBytes returns the next values of the sequence in big-endian order
+func (r *SequenceRandom) NextBytes(length int32) ([]byte, error) {
+	if length < 0 {
+		return nil, errors.New("negative length")
+	}
+
+	bytes := make([]byte, 0, length+3)
+	for int32(len(bytes)) < length {
+		value, _ := r.NextInt()
+		bytes = append(bytes, byte(value>>24), byte(value>>16), byte(value>>8), byte(value))
+	}
+	return bytes[:length], nil
+}
+
+// NewFactory returns a factory using the given clock and random, so that
+// the generated tsids only depend on them
+func NewFactory(clock *FakeClock, random tsid.Random) (*tsid.TsidFactory, error) {
+	return NewFactoryWithNode(clock, random, 0, 0)
+}
+
+// NewFactoryWithNode returns a factory like NewFactory, using the given
+// node bits and node id
+func NewFactoryWithNode(clock *FakeClock, random tsid.Random, nodeBits int32, node int32) (*tsid.TsidFactory, error) {
+	return tsid.TsidFactoryBuilder().
+		WithClock(clock).
+		WithRandom(random).
+		WithNodeBits(nodeBits).
+		WithNode(node).
+		NewInstance()
+}
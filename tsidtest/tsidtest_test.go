@@ -0,0 +1,98 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsidtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FakeClock(t *testing.T) {
+
+	t.Run("should only move when set or advanced", func(t *testing.T) {
+		start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+		clock := NewFakeClock(start)
+
+		assert.Equal(t, start.UnixMilli(), clock.UnixMilli())
+		assert.Equal(t, start.UnixMilli(), clock.UnixMilli())
+
+		clock.Advance(5 * time.Millisecond)
+		assert.Equal(t, start.UnixMilli()+5, clock.UnixMilli())
+
+		clock.Set(start)
+		assert.Equal(t, start, clock.Now())
+	})
+}
+
+func Test_SequenceRandom(t *testing.T) {
+
+	t.Run("should return values in order", func(t *testing.T) {
+		random := NewSequenceRandom(1, 2, 3)
+
+		for _, expected := range []int32{1, 2, 3, 1} {
+			value, err := random.NextInt()
+			assert.Nil(t, err)
+			assert.Equal(t, expected, value)
+		}
+	})
+
+	t.Run("should return values as bytes in big-endian order", func(t *testing.T) {
+		random := NewSequenceRandom(0x01020304, 0x05060708)
+
+		bytes, err := random.NextBytes(6)
+		assert.Nil(t, err)
+		assert.Equal(t, []byte{1, 2, 3, 4, 5, 6}, bytes)
+	})
+}
+
+func Test_NewFactory(t *testing.T) {
+
+	t.Run("should generate reproducible tsids", func(t *testing.T) {
+		golden := []string{
+			"03NFC9C000001",
+			"03NFC9C000002",
+			"03NFC9C040000",
+			"03NFC9C040001",
+			"03NFC9FX400AA",
+		}
+
+		for i := 0; i < 2; i++ {
+			clock := NewFakeClock(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))
+			random := NewSequenceRandom(0, 0, 0x14a, 0)
+
+			tsidFactory, err := NewFactoryWithNode(clock, random, 10, 0)
+			assert.Nil(t, err)
+
+			var actual []string
+			for j := 0; j < len(golden); j++ {
+				if j == 2 {
+					clock.Advance(time.Millisecond)
+				}
+				if j == 4 {
+					clock.Advance(time.Second)
+				}
+
+				tsid, err := tsidFactory.Generate()
+				assert.Nil(t, err)
+				actual = append(actual, tsid.ToString())
+			}
+			assert.Equal(t, golden, actual)
+		}
+	})
+}
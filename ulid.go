@@ -0,0 +1,181 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+const ULID_CHARS int32 = 26 // ULID strings are 26 characters long
+
+// ULID is a 128 bit identifier made of 48 bits of unix millis followed
+// by 80 random bits. Its string form uses Crockford's base32 alphabet,
+// like tsid
+type ULID [16]byte
+
+// ParseULID parses the 26 characters of a ulid. It is case insensitive,
+// and decodes the characters using ALPHABET_VALUES like FromString
+func ParseULID(str string) (ULID, error) {
+	if len(str) != int(ULID_CHARS) {
+		return ULID{}, fmt.Errorf("%w: expected %d characters, got %d", ErrInvalidULID, ULID_CHARS, len(str))
+	}
+
+	var hi, lo uint64
+	for i := 0; i < len(str); i++ {
+		c := str[i]
+		if c >= 128 || ALPHABET_VALUES[c] == -1 {
+			return ULID{}, fmt.Errorf("%w: invalid character %q at position %d", ErrInvalidULID, c, i)
+		}
+
+		// the first character only holds 3 of the 128 bits
+		if i == 0 && ALPHABET_VALUES[c] > 7 {
+			return ULID{}, fmt.Errorf("%w: first character out of range: %q", ErrInvalidULID, c)
+		}
+
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(ALPHABET_VALUES[c])
+	}
+
+	var u ULID
+	binary.BigEndian.PutUint64(u[0:8], hi)
+	binary.BigEndian.PutUint64(u[8:16], lo)
+	return u, nil
+}
+
+// String returns the 26 characters of the ulid in upper case
+func (u ULID) String() string {
+	hi := binary.BigEndian.Uint64(u[0:8])
+	lo := binary.BigEndian.Uint64(u[8:16])
+
+	chars := make([]rune, ULID_CHARS)
+	for i := len(chars) - 1; i >= 0; i-- {
+		chars[i] = ALPHABET_UPPERCASE[lo&0b11111]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(chars)
+}
+
+// UnixMillis returns the time of the ulid in millis since 1970-01-01
+func (u ULID) UnixMillis() int64 {
+	var millis int64 = 0
+
+	millis |= int64(u[0]) << 40
+	millis |= int64(u[1]) << 32
+	millis |= int64(u[2]) << 24
+	millis |= int64(u[3]) << 16
+	millis |= int64(u[4]) << 8
+	millis |= int64(u[5])
+
+	return millis
+}
+
+// Compare returns -1 if the ulid is less than the other, 0 if equal and
+// +1 if greater. Ulids sort by time and then by their random bits
+func (u ULID) Compare(other ULID) int {
+	return bytes.Compare(u[:], other[:])
+}
+
+// MarshalText encodes the ulid as its 26 characters
+func (u ULID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText decodes the 26 characters of a ulid
+func (u *ULID) UnmarshalText(data []byte) error {
+	parsed, err := ParseULID(string(data))
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
+
+// ToULID converts the tsid to a ulid. The unix millis of the tsid fill
+// the ulid time, and the 22 random bits (node and counter) the top of
+// its randomness. The remaining bits are zero, so that the ulids sort
+// like the tsids
+func (t Tsid) ToULID() ULID {
+	return t.ToULIDWithCustomEpoch(TSID_EPOCH)
+}
+
+// ToULIDWithCustomEpoch converts the tsid generated using the custom
+// epoch to a ulid
+func (t Tsid) ToULIDWithCustomEpoch(epoch int64) ULID {
+	var u ULID
+
+	millis := t.GetUnixMillisWithCustomEpoch(epoch)
+	random := t.GetRandom()
+
+	u[0] = byte(millis >> 40)
+	u[1] = byte(millis >> 32)
+	u[2] = byte(millis >> 24)
+	u[3] = byte(millis >> 16)
+	u[4] = byte(millis >> 8)
+	u[5] = byte(millis)
+
+	u[6] = byte(random >> 14)
+	u[7] = byte(random >> 6)
+	u[8] = byte(random << 2)
+
+	return u
+}
+
+// FromULID converts the ulid to a tsid. It returns ErrULIDPrecision if
+// the ulid has more random bits than the tsid can hold, or its time is
+// out of range of the tsid epoch. It reverses ToULID, use TruncateULID
+// to convert the ulids of another generator
+func FromULID(u ULID) (Tsid, error) {
+	return FromULIDWithCustomEpoch(u, TSID_EPOCH)
+}
+
+// FromULIDWithCustomEpoch converts the ulid to a tsid using the custom epoch
+func FromULIDWithCustomEpoch(u ULID, epoch int64) (Tsid, error) {
+	if u[8]&0b11 != 0 || u[9]|u[10]|u[11]|u[12]|u[13]|u[14]|u[15] != 0 {
+		return Nil, fmt.Errorf("%w: %s has more than %d random bits", ErrULIDPrecision, u, RANDOM_BITS)
+	}
+	return TruncateULIDWithCustomEpoch(u, epoch)
+}
+
+// TruncateULID converts any ulid to a tsid, keeping its time and the top
+// 22 bits of its randomness. Ulids in order convert to tsids in the same
+// order, but ulids which only differ below the top 22 random bits convert
+// to the same tsid. Within a millisecond, a collision is likely past a
+// couple thousand ulids, so check for duplicates when migrating keys.
+// It returns ErrULIDPrecision if the time is out of range of the epoch
+func TruncateULID(u ULID) (Tsid, error) {
+	return TruncateULIDWithCustomEpoch(u, TSID_EPOCH)
+}
+
+// TruncateULIDWithCustomEpoch converts any ulid to a tsid using the
+// custom epoch
+func TruncateULIDWithCustomEpoch(u ULID, epoch int64) (Tsid, error) {
+	time := u.UnixMillis() - epoch
+	if time < 0 || time >= 1<<42 {
+		return Nil, fmt.Errorf("%w: time of %s out of range of the epoch", ErrULIDPrecision, u)
+	}
+
+	var random int64 = 0
+
+	random |= int64(u[6]) << 14
+	random |= int64(u[7]) << 6
+	random |= int64(u[8]) >> 2
+
+	return NewTsid(time<<RANDOM_BITS | random), nil
+}
@@ -0,0 +1,166 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseULID(t *testing.T) {
+
+	t.Run("should parse and format ulid", func(t *testing.T) {
+		str := "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+
+		u, err := ParseULID(str)
+		assert.Nil(t, err)
+		assert.Equal(t, str, u.String())
+		assert.Equal(t, int64(1469922850259), u.UnixMillis())
+
+		lower, err := ParseULID(strings.ToLower(str))
+		assert.Nil(t, err)
+		assert.Equal(t, u, lower)
+
+		max, err := ParseULID("7ZZZZZZZZZZZZZZZZZZZZZZZZZ")
+		assert.Nil(t, err)
+		assert.Equal(t, ULID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+			0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, max)
+	})
+
+	t.Run("given invalid ulid should return error", func(t *testing.T) {
+		invalid := []string{
+			"",
+			"01ARZ3NDEKTSV4RRFFQ69G5FA",
+			"01ARZ3NDEKTSV4RRFFQ69G5FAU",
+			"01ARZ3NDEKTSV4RRFFQ69G5FAé",
+			"8ZZZZZZZZZZZZZZZZZZZZZZZZZ",
+		}
+
+		for _, str := range invalid {
+			_, err := ParseULID(str)
+			assert.True(t, errors.Is(err, ErrInvalidULID), str)
+		}
+	})
+
+	t.Run("should compare ulids", func(t *testing.T) {
+		a, _ := ParseULID("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+		b, _ := ParseULID("01ARZ3NDEKTSV4RRFFQ69G5FAW")
+
+		assert.Equal(t, -1, a.Compare(b))
+		assert.Equal(t, 1, b.Compare(a))
+		assert.Equal(t, 0, a.Compare(a))
+	})
+}
+
+func Test_ULID(t *testing.T) {
+
+	t.Run("should round trip tsids", func(t *testing.T) {
+		for i := 0; i < 1000; i++ {
+			tsid := Fast()
+
+			u := tsid.ToULID()
+			assert.Equal(t, tsid.GetUnixMillis(), u.UnixMillis())
+
+			parsed, err := ParseULID(u.String())
+			assert.Nil(t, err)
+
+			converted, err := FromULID(parsed)
+			assert.Nil(t, err)
+			assert.Equal(t, tsid, converted)
+		}
+
+		tsid := NewTsid(-1)
+		converted, err := FromULIDWithCustomEpoch(tsid.ToULIDWithCustomEpoch(DISCORD_EPOCH), DISCORD_EPOCH)
+		assert.Nil(t, err)
+		assert.Equal(t, tsid, converted)
+	})
+
+	t.Run("should preserve order", func(t *testing.T) {
+		tsids := []Tsid{NewTsid(1), NewTsid(1 << 21), NewTsid(1 << 22), NewTsid(1<<22 + 1), NewTsid(-1)}
+
+		for i := 1; i < len(tsids); i++ {
+			prev, next := tsids[i-1].ToULID(), tsids[i].ToULID()
+			assert.Equal(t, -1, prev.Compare(next))
+			assert.Less(t, prev.String(), next.String())
+		}
+	})
+
+	t.Run("given ulid with more precision should return error", func(t *testing.T) {
+		invalid := []string{
+			"01ARZ3NDEKTSV4RRFFQ69G5FAV", // random bits
+			"01ARZ3NDEK0000000000000000", // before epoch
+		}
+
+		for _, str := range invalid {
+			u, _ := ParseULID(str)
+			_, err := FromULID(u)
+			assert.True(t, errors.Is(err, ErrULIDPrecision), str)
+		}
+	})
+
+	t.Run("should truncate ulid randomness", func(t *testing.T) {
+		u, err := ParseULID("01KCRVE1XGTSV4RRFFQ69G5FAV")
+		assert.Nil(t, err)
+
+		tsid, err := TruncateULID(u)
+		assert.Nil(t, err)
+		assert.Equal(t, u.UnixMillis(), tsid.GetUnixMillis())
+
+		// keeps the top 22 random bits
+		truncated := tsid.ToULID()
+		assert.Equal(t, u[:8], truncated[:8])
+		assert.Equal(t, u[8]&^0b11, truncated[8])
+
+		// same as FromULID when the randomness fits
+		for i := 0; i < 100; i++ {
+			tsid := Fast()
+			converted, err := TruncateULID(tsid.ToULID())
+			assert.Nil(t, err)
+			assert.Equal(t, tsid, converted)
+		}
+
+		_, err = TruncateULID(ULID{})
+		assert.True(t, errors.Is(err, ErrULIDPrecision))
+	})
+
+	t.Run("should preserve order when truncating", func(t *testing.T) {
+		ulids := []string{
+			"01KCRVE1XGTSV4RRFFQ69G5FAV",
+			"01KCRVE1XGTSV4RRFFQ69G5FAW", // same top random bits
+			"01KCRVE1XGTSV8000000000000",
+			"01KCRVE1XHZZZZZZZZZZZZZZZZ",
+		}
+
+		prev := Nil
+		for i, str := range ulids {
+			u, err := ParseULID(str)
+			assert.Nil(t, err)
+			tsid, err := TruncateULID(u)
+			assert.Nil(t, err)
+
+			if i == 1 {
+				assert.Equal(t, prev, tsid)
+			} else {
+				assert.True(t, prev.Before(tsid), str)
+			}
+			prev = tsid
+		}
+	})
+}
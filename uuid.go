@@ -0,0 +1,175 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+// UUID is a 128 bit universally unique identifier
+type UUID [16]byte
+
+// ParseUUID parses the canonical hyphenated form of a uuid,
+// e.g. 0188e3c2-7a1b-7c3d-8000-000000000000. It is case insensitive
+func ParseUUID(str string) (UUID, error) {
+	var u UUID
+	if len(str) != 36 {
+		return u, fmt.Errorf("%w: expected 36 characters, got %d", ErrInvalidUUID, len(str))
+	}
+
+	if str[8] != '-' || str[13] != '-' || str[18] != '-' || str[23] != '-' {
+		return u, fmt.Errorf("%w: missing hyphen: %q", ErrInvalidUUID, str)
+	}
+
+	hexStr := str[0:8] + str[9:13] + str[14:18] + str[19:23] + str[24:36]
+	if _, err := hex.Decode(u[:], []byte(hexStr)); err != nil {
+		return UUID{}, fmt.Errorf("%w: %q", ErrInvalidUUID, str)
+	}
+	return u, nil
+}
+
+// String returns the canonical hyphenated form of the uuid in lowercase
+func (u UUID) String() string {
+	buf := make([]byte, 36)
+
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:36], u[10:16])
+
+	return string(buf)
+}
+
+// Version returns the version of the uuid
+func (u UUID) Version() int {
+	return int(u[6] >> 4)
+}
+
+// MarshalText encodes the uuid in the canonical hyphenated form
+func (u UUID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText decodes the canonical hyphenated form of a uuid
+func (u *UUID) UnmarshalText(data []byte) error {
+	parsed, err := ParseUUID(string(data))
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
+
+// ToUUID embeds the tsid into the first 8 bytes of a uuid, the remaining
+// bytes are zero. The uuid sorts like the tsid but has no version
+func (t Tsid) ToUUID() UUID {
+	var u UUID
+	copy(u[:], t.ToBytes())
+	return u
+}
+
+// FromUUID returns the tsid embedded using ToUUID
+func FromUUID(u UUID) (Tsid, error) {
+	for _, b := range u[8:] {
+		if b != 0 {
+			return Nil, fmt.Errorf("%w: %s does not embed a tsid", ErrInvalidUUID, u)
+		}
+	}
+	return FromBytes(u[:8])
+}
+
+// ToUUIDv7 embeds the tsid into a RFC 9562 version 7 uuid. The unix millis
+// of the tsid fill the 48 bit timestamp, and the 22 random bits (node and
+// counter) the top of rand_a and rand_b. The remaining bits are zero, so
+// that the uuids sort like the tsids
+func (t Tsid) ToUUIDv7() UUID {
+	return t.ToUUIDv7WithCustomEpoch(TSID_EPOCH)
+}
+
+// ToUUIDv7WithCustomEpoch embeds the tsid generated using the custom
+// epoch into a version 7 uuid
+func (t Tsid) ToUUIDv7WithCustomEpoch(epoch int64) UUID {
+	var u UUID
+
+	millis := t.GetUnixMillisWithCustomEpoch(epoch)
+	random := t.GetRandom()
+
+	u[0] = byte(millis >> 40)
+	u[1] = byte(millis >> 32)
+	u[2] = byte(millis >> 24)
+	u[3] = byte(millis >> 16)
+	u[4] = byte(millis >> 8)
+	u[5] = byte(millis)
+
+	// version and the top 12 random bits in rand_a
+	u[6] = 0x70 | byte(random>>18)&0x0f
+	u[7] = byte(random >> 10)
+
+	// variant and the low 10 random bits at the top of rand_b
+	u[8] = 0x80 | byte(random>>4)&0x3f
+	u[9] = byte(random<<4) & 0xf0
+
+	return u
+}
+
+// FromUUIDv7 returns the tsid embedded using ToUUIDv7. Version 7 uuids
+// which were not created from a tsid can not be converted without losing
+// their random bits, so they return ErrInvalidUUID
+func FromUUIDv7(u UUID) (Tsid, error) {
+	return FromUUIDv7WithCustomEpoch(u, TSID_EPOCH)
+}
+
+// FromUUIDv7WithCustomEpoch returns the tsid embedded using
+// ToUUIDv7WithCustomEpoch
+func FromUUIDv7WithCustomEpoch(u UUID, epoch int64) (Tsid, error) {
+	if u.Version() != 7 || u[8]>>6 != 0x02 {
+		return Nil, fmt.Errorf("%w: %s is not a version 7 uuid", ErrInvalidUUID, u)
+	}
+
+	if u[9]&0x0f != 0 || u[10]|u[11]|u[12]|u[13]|u[14]|u[15] != 0 {
+		return Nil, fmt.Errorf("%w: %s does not embed a tsid", ErrInvalidUUID, u)
+	}
+
+	var millis int64 = 0
+
+	millis |= int64(u[0]) << 40
+	millis |= int64(u[1]) << 32
+	millis |= int64(u[2]) << 24
+	millis |= int64(u[3]) << 16
+	millis |= int64(u[4]) << 8
+	millis |= int64(u[5])
+
+	time := millis - epoch
+	if time < 0 || time >= 1<<42 {
+		return Nil, fmt.Errorf("%w: timestamp of %s out of range of the epoch", ErrInvalidUUID, u)
+	}
+
+	var random int64 = 0
+
+	random |= int64(u[6]&0x0f) << 18
+	random |= int64(u[7]) << 10
+	random |= int64(u[8]&0x3f) << 4
+	random |= int64(u[9]) >> 4
+
+	return NewTsid(time<<RANDOM_BITS | random), nil
+}
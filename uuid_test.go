@@ -0,0 +1,143 @@
+/*
+Copyright (c) 2023 Vishal Bihani
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tsid
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseUUID(t *testing.T) {
+
+	t.Run("should parse and format canonical uuid", func(t *testing.T) {
+		str := "0188e3c2-7a1b-7c3d-8abc-0123456789ab"
+
+		u, err := ParseUUID(str)
+		assert.Nil(t, err)
+		assert.Equal(t, str, u.String())
+		assert.Equal(t, 7, u.Version())
+
+		upper, err := ParseUUID("0188E3C2-7A1B-7C3D-8ABC-0123456789AB")
+		assert.Nil(t, err)
+		assert.Equal(t, u, upper)
+	})
+
+	t.Run("given invalid uuid should return error", func(t *testing.T) {
+		invalid := []string{
+			"",
+			"0188e3c27a1b7c3d8abc0123456789ab",
+			"0188e3c2-7a1b-7c3d-8abc_0123456789ab",
+			"0188e3c2-7a1b-7c3d-8abc-0123456789ag",
+			"0188e3c2-7a1b-7c3d-8abc-0123456789abc",
+		}
+
+		for _, str := range invalid {
+			_, err := ParseUUID(str)
+			assert.True(t, errors.Is(err, ErrInvalidUUID), str)
+		}
+	})
+
+	t.Run("should marshal as text", func(t *testing.T) {
+		u, _ := ParseUUID("0188e3c2-7a1b-7c3d-8abc-0123456789ab")
+
+		data, err := json.Marshal(u)
+		assert.Nil(t, err)
+		assert.Equal(t, `"0188e3c2-7a1b-7c3d-8abc-0123456789ab"`, string(data))
+
+		var decoded UUID
+		assert.Nil(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, u, decoded)
+	})
+}
+
+func Test_UUIDv7(t *testing.T) {
+
+	t.Run("should embed tsid into uuid v7", func(t *testing.T) {
+		tsid, _ := FromString("0AWE5HZP3SKTK")
+
+		u := tsid.ToUUIDv7()
+		assert.Equal(t, 7, u.Version())
+		assert.Equal(t, byte(0x80), u[8]&0xc0)
+
+		var millis int64
+		for _, b := range u[:6] {
+			millis = millis<<8 | int64(b)
+		}
+		assert.Equal(t, tsid.GetUnixMillis(), millis)
+	})
+
+	t.Run("should round trip tsids", func(t *testing.T) {
+		for i := 0; i < 1000; i++ {
+			tsid := Fast()
+
+			u := tsid.ToUUIDv7()
+			parsed, err := ParseUUID(u.String())
+			assert.Nil(t, err)
+
+			converted, err := FromUUIDv7(parsed)
+			assert.Nil(t, err)
+			assert.Equal(t, tsid, converted)
+		}
+
+		tsid := NewTsid(-1)
+		converted, err := FromUUIDv7WithCustomEpoch(tsid.ToUUIDv7WithCustomEpoch(DISCORD_EPOCH), DISCORD_EPOCH)
+		assert.Nil(t, err)
+		assert.Equal(t, tsid, converted)
+	})
+
+	t.Run("should preserve order", func(t *testing.T) {
+		tsids := []Tsid{NewTsid(1), NewTsid(1 << 21), NewTsid(1 << 22), NewTsid(1<<22 + 1), NewTsid(-1)}
+
+		for i := 1; i < len(tsids); i++ {
+			prev, next := tsids[i-1].ToUUIDv7().String(), tsids[i].ToUUIDv7().String()
+			assert.Less(t, prev, next)
+		}
+	})
+
+	t.Run("given uuid not embedding tsid should return error", func(t *testing.T) {
+		invalid := []string{
+			"0188e3c2-7a1b-4c3d-8abc-000000000000", // version 4
+			"0188e3c2-7a1b-7c3d-cab0-000000000000", // variant
+			"0188e3c2-7a1b-7c3d-8ab0-000000000001", // random padding
+			"00000000-0000-7000-8000-000000000000", // before epoch
+		}
+
+		for _, str := range invalid {
+			u, _ := ParseUUID(str)
+			_, err := FromUUIDv7(u)
+			assert.True(t, errors.Is(err, ErrInvalidUUID), str)
+		}
+	})
+}
+
+func Test_UUID(t *testing.T) {
+	tsid := Fast()
+
+	u := tsid.ToUUID()
+	assert.Equal(t, tsid.ToBytes(), u[:8])
+
+	converted, err := FromUUID(u)
+	assert.Nil(t, err)
+	assert.Equal(t, tsid, converted)
+
+	u[15] = 1
+	_, err = FromUUID(u)
+	assert.True(t, errors.Is(err, ErrInvalidUUID))
+}